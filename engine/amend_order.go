@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// AmendOrder 修改订单价格与数量，newPrice/newAmount 为 nil 表示不修改
+// - 价格不变且数量减少：原地修改 Arena 中的订单与 OrderNode.Volume，保留时间优先级
+// - 价格变化或数量增加：在同一临界区内撤单并以相同 ID 重新下单（可能立即成交），失去时间优先级
+// 修改后生成 amended 事件与 replaced 执行报告；返回订单修改（及撤单重下后撮合）后的状态副本，
+// 订单已完全成交或被撤销时数量为 0
+func (ob *OrderBook) AmendOrder(id string, newPrice, newAmount *util.StandardBigDecimal) (*Order, error) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+
+	if newPrice != nil && newPrice.Cmp(decimalZero) != 1 {
+		return nil, errors.New("Order price should be greater than zero")
+	}
+	if newAmount != nil && newAmount.Cmp(decimalZero) != 1 {
+		return nil, errors.New("Order amount should be greater than zero")
+	}
+	if err := ob.checkAmend(id, newPrice, newAmount); err != nil {
+		return nil, err
+	}
+
+	idx, ok := ob.lookup(id)
+	if !ok {
+		if stop, ok := ob.stop(id); ok {
+			return ob.amendStopOrder(stop, newPrice, newAmount), nil
+		}
+		return nil, errors.New("no Order found")
+	}
+
+	orderInArena := ob.Arena.Get(idx)
+	remaining := orderInArena.remaining()
+	samePrice := newPrice == nil || newPrice.Cmp(orderInArena.Price) == 0
+	if samePrice && (newAmount == nil || newAmount.Cmp(remaining) == 0) {
+		return NewOrder(orderInArena.ID, orderInArena.Type, remaining, orderInArena.Price.Clone()), nil
+	}
+
+	if samePrice && newAmount.Cmp(remaining) == -1 {
+		ob.reduceOrder(orderInArena, remaining.Sub(newAmount))
+		ob.emit(EventAmended, orderInArena, "")
+		return NewOrder(orderInArena.ID, orderInArena.Type, orderInArena.remaining(), orderInArena.Price.Clone()), nil
+	}
+
+	if ob.state == StateHalted || ob.state == StateClosed || ob.state == StateResolved {
+		// 撤单重下会被拒绝，熔断与休市期间只允许原地减量
+		return nil, errors.New("trading halted")
+	}
+
+	// 撤单重下：复制订单属性，替换价格与数量
+	replacement := *orderInArena
+	replacement.Next = NullIndex
+	replacement.Prev = NullIndex
+	replacement.Node = NullIndex
+	replacement.hidden = nil
+	replacement.Amount = remaining
+	if newAmount != nil {
+		replacement.Amount = newAmount.Clone()
+		// 委托数量改为已成交数量加新的剩余数量
+		replacement.quantity = replacement.filled + newAmount.Val
+	}
+	replacement.Price = orderInArena.Price.Clone()
+	if newPrice != nil {
+		replacement.Price = newPrice.Clone()
+	}
+
+	ob.removeIndex(idx)
+	ob.emit(EventAmended, &replacement, "")
+	ob.process(replacement)
+	ob.triggerStops()
+	if idx, ok := ob.lookup(id); ok {
+		rest := ob.Arena.Get(idx)
+		return NewOrder(rest.ID, rest.Type, rest.remaining(), rest.Price.Clone()), nil
+	}
+	return NewOrder(replacement.ID, replacement.Type, &util.StandardBigDecimal{}, replacement.Price.Clone()), nil
+}
+
+// checkAmend 按交易规则检查修改后的订单（订单不存在时由调用方处理）
+func (ob *OrderBook) checkAmend(id string, newPrice, newAmount *util.StandardBigDecimal) error {
+	var probe Order
+	market := false
+	if idx, ok := ob.lookup(id); ok {
+		order := ob.Arena.Get(idx)
+		probe = Order{Price: order.Price, Amount: order.remaining()}
+	} else if stop, ok := ob.stop(id); ok {
+		probe = Order{Price: stop.order.Price, Amount: stop.order.Amount, Funds: stop.order.Funds}
+		market = stop.market
+	} else {
+		return nil
+	}
+	if newPrice != nil {
+		probe.Price = newPrice
+	}
+	if newAmount != nil {
+		probe.Amount = newAmount
+	}
+	return ob.spec.Validate(&probe, market)
+}
+
+// reduceOrder 原地减少挂单数量（委托数量同步减少），冰山单优先扣减隐藏储备
+func (ob *OrderBook) reduceOrder(order *Order, delta *util.StandardBigDecimal) {
+	order.quantity -= delta.Val
+	if order.hidden != nil {
+		if order.hidden.Cmp(delta) != -1 {
+			order.hidden.SubMut(delta)
+			return
+		}
+		delta = delta.Sub(order.hidden)
+		order.hidden.SetZero()
+	}
+	order.Amount.SubMut(delta)
+	ob.Levels.Get(order.Node).updateVolume(delta.Neg())
+}
+
+// amendStopOrder 修改未触发条件单的限价与数量（条件单按触发价排队，不涉及时间优先级）
+func (ob *OrderBook) amendStopOrder(stop *stopOrder, newPrice, newAmount *util.StandardBigDecimal) *Order {
+	if newPrice != nil {
+		stop.order.Price = newPrice.Clone()
+	}
+	if newAmount != nil {
+		stop.order.Amount = newAmount.Clone()
+		if stop.order.Funds == nil {
+			stop.order.quantity = stop.order.filled + newAmount.Val
+		}
+	}
+	ob.emit(EventAmended, &stop.order, "")
+	return NewOrder(stop.order.ID, stop.order.Type, stop.order.Amount.Clone(), stop.order.Price.Clone())
+}
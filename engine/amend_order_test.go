@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestAmendReduceKeepsPriority(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("5.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("5.0"), DecimalBig("100.0")))
+
+	amended, err := ob.AmendOrder("s1", nil, DecimalBig("2.0"))
+	if err != nil || amended.Amount.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatalf("amend should reduce amount in place (have: %v, %v)", amended, err)
+	}
+	if vol := &ob.Levels.Get(ob.Arena.Get(indexOf(ob, "s1")).Node).Volume; vol.Cmp(DecimalBig("7.0")) != 0 {
+		t.Fatalf("level volume should be reduced (have: %s)", vol)
+	}
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "s1" {
+		t.Fatalf("reduced order should keep time priority (have: %+v)", listener.Trades)
+	}
+}
+
+func TestAmendIncreaseLosesPriority(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if _, err := ob.AmendOrder("s1", nil, DecimalBig("3.0")); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "s2" {
+		t.Fatalf("increased order should go to the back of the queue (have: %+v)", listener.Trades)
+	}
+}
+
+func TestAmendPriceCanMatch(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("2.0"), DecimalBig("100.0")))
+
+	amended, err := ob.AmendOrder("b1", DecimalBig("101.0"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amended.Amount.Cmp(DecimalBig("1.0")) != 0 || amended.Price.Cmp(DecimalBig("101.0")) != 0 {
+		t.Fatalf("amend should return the state after matching (have: %s @ %s)", amended.Amount, amended.Price)
+	}
+	if len(listener.Trades) != 1 || listener.Trades[0].TakerID != "b1" {
+		t.Fatalf("repriced order should match as taker (have: %+v)", listener.Trades)
+	}
+	if _, ok := ob.lookup("s1"); ok {
+		t.Fatal("maker should be filled")
+	}
+	rest := ob.Arena.Get(indexOf(ob, "b1"))
+	if rest.Price.Cmp(DecimalBig("101.0")) != 0 || rest.Amount.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("remainder should rest at the new price (have: %s @ %s)", rest.Amount, rest.Price)
+	}
+	if ob.BuyTree.Min() == nil || ob.BuyTree.Min() != ob.Levels.Get(rest.Node) || ob.BuyTree.Len() != 1 {
+		t.Fatal("old price level should be removed")
+	}
+}
+
+func TestAmendIcebergReducesHiddenFirst(t *testing.T) {
+	ob := NewOrderBook(&MockListener{})
+	ob.Process(newIceberg("ice", Sell, "10.0", "2.0", "100.0"))
+
+	if _, err := ob.AmendOrder("ice", nil, DecimalBig("5.0")); err != nil {
+		t.Fatal(err)
+	}
+	ice := ob.Arena.Get(indexOf(ob, "ice"))
+	if ice.Amount.Cmp(DecimalBig("2.0")) != 0 || ice.hidden.Cmp(DecimalBig("3.0")) != 0 {
+		t.Fatalf("hidden reserve should shrink first (have: %s/%s)", ice.Amount, ice.hidden)
+	}
+
+	if _, err := ob.AmendOrder("ice", nil, DecimalBig("1.0")); err != nil {
+		t.Fatal(err)
+	}
+	if ice.Amount.Cmp(DecimalBig("1.0")) != 0 || ob.Levels.Get(ice.Node).Volume.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("visible amount should shrink after hidden is gone (have: %s)", ice.Amount)
+	}
+}
+
+func TestAmendErrors(t *testing.T) {
+	ob := NewOrderBook(&MockListener{})
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if _, err := ob.AmendOrder("missing", nil, DecimalBig("1.0")); err == nil {
+		t.Fatal("amending an unknown order should fail")
+	}
+	if _, err := ob.AmendOrder("b1", nil, DecimalBig("0.0")); err == nil {
+		t.Fatal("amending to zero amount should fail")
+	}
+}
+
+func TestAmendStopOrder(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newStop("stop", Buy, "1.0", "105.0", "101.0"))
+
+	amended, err := ob.AmendOrder("stop", DecimalBig("106.0"), DecimalBig("2.0"))
+	if err != nil || amended.Price.Cmp(DecimalBig("106.0")) != 0 || amended.Amount.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatalf("pending stop should be amended (have: %v, %v)", amended, err)
+	}
+}
+
+func TestAmendFullyFilledReturnsZero(t *testing.T) {
+	ob := NewOrderBook(&MockListener{})
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	amended, err := ob.AmendOrder("b1", DecimalBig("101.0"), nil)
+	if err != nil || amended.Amount.Val != 0 {
+		t.Fatalf("filled replacement should return zero amount (have: %v, %v)", amended, err)
+	}
+}
+
+func TestAmendExecutionReports(t *testing.T) {
+	listener := &execListener{}
+	ob := NewOrderBook(ExecutionAdapter{listener})
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("5.0"), DecimalBig("100.0")))
+
+	if _, err := ob.AmendOrder("b1", nil, DecimalBig("3.0")); err != nil {
+		t.Fatal(err)
+	}
+	r := listener.last("b1")
+	if r.ExecType != ExecReplaced || r.Status != StatusNew || r.Quantity != DecimalBig("3.0").Val || r.LeavesAmount != DecimalBig("3.0").Val {
+		t.Fatalf("in-place reduce should report the new leaves quantity (have: %+v)", r)
+	}
+
+	if _, err := ob.AmendOrder("b1", DecimalBig("99.0"), nil); err != nil {
+		t.Fatal(err)
+	}
+	n := len(listener.Reports)
+	if n < 2 || listener.Reports[n-2].ExecType != ExecReplaced || listener.Reports[n-2].Price != DecimalBig("99.0").Val || listener.Reports[n-1].ExecType != ExecNew {
+		t.Fatalf("cancel/replace should report the replacement before it rests (have: %+v)", listener.Reports)
+	}
+
+	ob.Process(newStop("stop", Buy, "1.0", "105.0", "101.0"))
+	if _, err := ob.AmendOrder("stop", DecimalBig("106.0"), DecimalBig("2.0")); err != nil {
+		t.Fatal(err)
+	}
+	r = listener.last("stop")
+	if r.ExecType != ExecReplaced || r.Price != DecimalBig("106.0").Val || r.Quantity != DecimalBig("2.0").Val || r.LeavesAmount != DecimalBig("2.0").Val {
+		t.Fatalf("pending stop amend should be reported (have: %+v)", r)
+	}
+}
@@ -109,3 +109,64 @@ func (a *OrderArena) Reset() {
 	}
 	a.freeHead = NullIndex
 }
+
+const (
+	// LevelPageShift 价格档位分页参数：2^10 = 1024（档位数远少于订单数，分页更小）
+	LevelPageShift = 10
+	LevelPageSize  = 1 << LevelPageShift
+	LevelPageMask  = LevelPageSize - 1
+)
+
+// LevelArena 价格档位内存池（分页式连续内存布局，与 OrderArena 相同）
+// 订单到档位（Order.Node）以及档位之间（价格树链接）均通过索引引用，档位的新建与回收不产生堆分配
+type LevelArena struct {
+	pages    [][]OrderNode
+	freeHead IndexType // 空闲链表头（空闲档位的 left 字段存储下一个空闲档位的索引）
+}
+
+// NewLevelArena 创建一个新的价格档位内存池
+func NewLevelArena() *LevelArena {
+	return &LevelArena{
+		pages:    [][]OrderNode{make([]OrderNode, 0, LevelPageSize)},
+		freeHead: NullIndex,
+	}
+}
+
+// Alloc 分配一个空档位，返回索引
+func (a *LevelArena) Alloc() IndexType {
+	idx := a.freeHead
+	if idx != NullIndex {
+		a.freeHead = a.Get(idx).left
+	} else {
+		last := len(a.pages) - 1
+		if len(a.pages[last]) >= LevelPageSize {
+			a.pages = append(a.pages, make([]OrderNode, 0, LevelPageSize))
+			last++
+		}
+		idx = IndexType(last<<LevelPageShift | len(a.pages[last]))
+		a.pages[last] = append(a.pages[last], OrderNode{})
+	}
+
+	*a.Get(idx) = OrderNode{
+		Head:  NullIndex,
+		Tail:  NullIndex,
+		index: idx,
+		left:  NullIndex,
+		right: NullIndex,
+	}
+	return idx
+}
+
+// Free 回收指定索引的档位
+func (a *LevelArena) Free(idx IndexType) {
+	if idx == NullIndex {
+		return
+	}
+	a.Get(idx).left = a.freeHead
+	a.freeHead = idx
+}
+
+// Get 通过索引获取档位指针
+func (a *LevelArena) Get(idx IndexType) *OrderNode {
+	return &a.pages[int(idx)>>LevelPageShift][int(idx)&LevelPageMask]
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// auctionLevel 集合竞价计算用的价格档位汇总
+type auctionLevel struct {
+	price  *util.StandardBigDecimal
+	volume int64
+}
+
+// StartAuction 进入集合竞价阶段：限价单只挂单不撮合（允许买卖价交叉），直到调用 Uncross
+// 竞价期间市价单与 IOC/FOK 订单被拒绝，条件单照常进入条件单簿
+func (ob *OrderBook) StartAuction() {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.setState(StateAuction, StateChangeManual)
+}
+
+// InAuction 返回订单簿是否处于集合竞价阶段
+func (ob *OrderBook) InAuction() bool {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	return ob.state == StateAuction
+}
+
+// Uncross 结束集合竞价：计算均衡价格并以该单一价格执行所有可成交订单，之后恢复连续撮合
+// 均衡价格依次按以下规则选取：可成交量最大；未成交余量（买卖量差）最小；距参考价 reference 最近
+// （reference 为 nil 时使用最新成交价）；仍相同时取较低价格
+// 成交按价格-时间优先在买卖双方间分配，通过 OnTrade 以卖单为 Maker、买单为 Taker 上报；
+// 已被兄弟腿触发的订单组成员在撮合前撤销，同一账户的买卖单按买单（Taker）的 STP 模式处理。
+// 返回的成交量为实际成交量，无交叉（或撤销后不再交叉）时返回 nil
+func (ob *OrderBook) Uncross(reference *util.StandardBigDecimal) (price, volume *util.StandardBigDecimal) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+	return ob.uncross(reference)
+}
+
+// uncross 集合竞价撮合（调用方持有锁）
+func (ob *OrderBook) uncross(reference *util.StandardBigDecimal) (price, volume *util.StandardBigDecimal) {
+	ob.setState(StateContinuous, StateChangeManual)
+	ref := ob.lastPrice
+	if reference != nil {
+		ref = reference.Val
+	}
+	// 先撤销已触发订单组的兄弟订单，不计入可成交量
+	ob.settleGroups()
+	price, executable := ob.equilibrium(ref)
+	if price == nil {
+		return nil, nil
+	}
+
+	var filled int64
+	for filled < executable {
+		bidNode, askNode := ob.bestNode(ob.BuyTree, Sell), ob.bestNode(ob.SellTree, Buy)
+		if bidNode == nil || askNode == nil || bidNode.price < price.Val || askNode.price > price.Val {
+			// 撤销兄弟订单或自成交防护后剩余订单不再以均衡价交叉
+			break
+		}
+		bidIdx, askIdx := bidNode.Head, askNode.Head
+		bid, ask := ob.Arena.Get(bidIdx), ob.Arena.Get(askIdx)
+		if bid.siblingDone() || ask.siblingDone() || isSelfTrade(bid, ask) {
+			ob.cancelUncrossable(bidNode, bidIdx, askNode, askIdx)
+			continue
+		}
+
+		fill := executable - filled
+		if bid.Amount.Val < fill {
+			fill = bid.Amount.Val
+		}
+		if ask.Amount.Val < fill {
+			fill = ask.Amount.Val
+		}
+		ob.onTrade(ask, bid, price.Val, fill)
+		filled += fill
+
+		ob.fillResting(bidNode, bidIdx, fill)
+		ob.fillResting(askNode, askIdx, fill)
+	}
+
+	ob.triggerStops()
+	if filled == 0 {
+		return nil, nil
+	}
+	return price, &util.StandardBigDecimal{Val: filled}
+}
+
+// cancelUncrossable 撤销档首不能在竞价中相互成交的订单：已被兄弟腿触发的订单组成员，
+// 或同一账户的买卖单（按买单即 Taker 的 STP 模式撤销或扣减）
+func (ob *OrderBook) cancelUncrossable(bidNode *OrderNode, bidIdx IndexType, askNode *OrderNode, askIdx IndexType) {
+	bid, ask := ob.Arena.Get(bidIdx), ob.Arena.Get(askIdx)
+	cancelBid, cancelAsk := bid.siblingDone(), ask.siblingDone()
+	reason := CancelOrderGroup
+	if !cancelBid && !cancelAsk {
+		reason = CancelSelfTrade
+		if ob.stpListener != nil {
+			ob.stpListener.OnSelfTradePrevented(ask.ID, bid.ID, bid.STP)
+		}
+		switch bid.STP {
+		case STPCancelOldest:
+			cancelAsk = true
+		case STPCancelBoth:
+			cancelBid, cancelAsk = true, true
+		case STPDecrementAndCancel:
+			bidLeaves, askLeaves := bid.remainingVal(), ask.remainingVal()
+			switch {
+			case bidLeaves > askLeaves:
+				ob.reduceOrder(bid, &util.StandardBigDecimal{Val: askLeaves})
+				cancelAsk = true
+			case bidLeaves < askLeaves:
+				ob.reduceOrder(ask, &util.StandardBigDecimal{Val: bidLeaves})
+				cancelBid = true
+			default:
+				cancelBid, cancelAsk = true, true
+			}
+		default:
+			cancelBid = true
+		}
+	}
+
+	if cancelBid {
+		ob.cancelMaker(bidNode, bidIdx, reason)
+		if bidNode.Count == 0 {
+			ob.releaseLevel(ob.BuyTree, bidNode)
+		}
+	}
+	if cancelAsk {
+		ob.cancelMaker(askNode, askIdx, reason)
+		if askNode.Count == 0 {
+			ob.releaseLevel(ob.SellTree, askNode)
+		}
+	}
+}
+
+// equilibrium 计算均衡价格及该价格下的可成交量，无交叉时返回 nil
+func (ob *OrderBook) equilibrium(reference int64) (*util.StandardBigDecimal, int64) {
+	bids := ob.auctionLevels(Buy)
+	asks := ob.auctionLevels(Sell)
+	if len(bids) == 0 || len(asks) == 0 || bids[0].price.Cmp(asks[0].price) == -1 {
+		return nil, 0
+	}
+
+	// 候选价格为双方所有档位价格，升序排列
+	candidates := make([]*util.StandardBigDecimal, 0, len(bids)+len(asks))
+	for _, level := range bids {
+		candidates = append(candidates, level.price)
+	}
+	for _, level := range asks {
+		candidates = append(candidates, level.price)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Val < candidates[j].Val })
+
+	// 价格升序扫描：买方累计量（价格 >= p）递减，卖方累计量（价格 <= p）递增
+	var demand, supply int64
+	for _, level := range bids {
+		demand += level.volume
+	}
+	bi, ai := len(bids)-1, 0
+
+	var best *util.StandardBigDecimal
+	var bestVolume, bestImbalance, bestDistance int64
+	for _, p := range candidates {
+		for ; ai < len(asks) && asks[ai].price.Cmp(p) != 1; ai++ {
+			supply += asks[ai].volume
+		}
+		for ; bi >= 0 && bids[bi].price.Cmp(p) == -1; bi-- {
+			demand -= bids[bi].volume
+		}
+		volume, imbalance := demand, supply-demand
+		if supply < demand {
+			volume, imbalance = supply, demand-supply
+		}
+		distance := p.Val - reference
+		if distance < 0 {
+			distance = -distance
+		}
+
+		better := best == nil || volume > bestVolume ||
+			volume == bestVolume && (imbalance < bestImbalance || imbalance == bestImbalance && distance < bestDistance)
+		if better {
+			best, bestVolume, bestImbalance, bestDistance = p, volume, imbalance, distance
+		}
+	}
+	if bestVolume == 0 {
+		return nil, 0
+	}
+	return best, bestVolume
+}
+
+// auctionLevels 汇总单边订单簿各档位的剩余数量（含冰山单隐藏部分，同一 OCO 订单组只计一条腿），按价格优先顺序排列
+func (ob *OrderBook) auctionLevels(side Side) []auctionLevel {
+	tree := ob.BuyTree
+	if side == Sell {
+		tree = ob.SellTree
+	}
+	var levels []auctionLevel
+	var groups []*orderGroup // 已计入数量的 OCO 订单组
+	tree.Walk(side == Sell, func(_ int64, node *OrderNode) bool {
+		level := auctionLevel{price: ob.Arena.Get(node.Head).Price}
+		for idx := node.Head; idx != NullIndex; idx = ob.Arena.Get(idx).Next {
+			order := ob.Arena.Get(idx)
+			if order.siblingDone() || hasGroup(groups, order.group) {
+				// OCO 任一腿成交即撤销其余腿：同组只计入最先成交的一条腿
+				continue
+			}
+			level.volume += order.remainingVal()
+			if order.group != nil && order.group.exits == nil {
+				groups = append(groups, order.group)
+			}
+		}
+		levels = append(levels, level)
+		return true
+	})
+	return levels
+}
+
+// fillResting 挂单成交 fill 数量：完全成交时移出订单簿（冰山单刷新后排到队尾），档位为空时移除档位
+func (ob *OrderBook) fillResting(node *OrderNode, idx IndexType, fill int64) {
+	order := ob.Arena.Get(idx)
+	if order.Amount.Val > fill {
+		order.Amount.Val -= fill
+		node.Volume.Val -= fill
+		return
+	}
+	ob.removeFilledMaker(node, idx)
+	if node.Count == 0 {
+		// removeOrder 只使用价格与方向，Arena 数据在当前锁范围内依然可读
+		ob.removeOrder(order)
+	}
+}
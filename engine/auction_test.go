@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestAuctionAccumulatesCrossedOrders(t *testing.T) {
+	listener := &rejectListener{}
+	ob := NewOrderBook(listener)
+	ob.StartAuction()
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("105.0")))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if len(listener.Trades) != 0 || ob.orders.size() != 2 {
+		t.Fatal("auction should not match crossed orders")
+	}
+
+	ioc := NewOrder("ioc", Buy, DecimalBig("1.0"), DecimalBig("105.0"))
+	ioc.TimeInForce = IOC
+	ob.Process(*ioc)
+	ob.ProcessMarket(*NewOrder("m", Buy, DecimalBig("1.0"), DecimalBig("1.0")))
+	if listener.Rejected["ioc"] != RejectAuction || listener.Rejected["m"] != RejectAuction {
+		t.Fatalf("IOC and market orders should be rejected during auction (have: %v)", listener.Rejected)
+	}
+}
+
+func TestUncrossEquilibrium(t *testing.T) {
+	var tests = []struct {
+		name   string
+		buys   [][2]string
+		sells  [][2]string
+		ref    string
+		price  string
+		volume string
+	}{
+		// 最大成交量（100 与 101 成交量、余量相同，参考价 101）
+		{"max volume", [][2]string{{"3.0", "102.0"}, {"2.0", "101.0"}, {"4.0", "99.0"}}, [][2]string{{"1.0", "98.0"}, {"3.0", "100.0"}, {"5.0", "103.0"}}, "101.0", "101.0", "4.0"},
+		// 成交量优先于参考价
+		{"volume over reference", [][2]string{{"5.0", "101.0"}}, [][2]string{{"2.0", "100.0"}, {"3.0", "101.0"}}, "100.0", "101.0", "5.0"},
+		// 成交量相同时最小未成交余量：100 处买 5/卖 3，101 处买 3/卖 3
+		{"min imbalance", [][2]string{{"3.0", "101.0"}, {"2.0", "100.0"}}, [][2]string{{"3.0", "99.0"}}, "99.0", "101.0", "3.0"},
+		// 成交量与余量相同时取距参考价最近的价格
+		{"reference high", [][2]string{{"2.0", "105.0"}}, [][2]string{{"2.0", "100.0"}}, "104.0", "105.0", "2.0"},
+		{"reference low", [][2]string{{"2.0", "105.0"}}, [][2]string{{"2.0", "100.0"}}, "101.0", "100.0", "2.0"},
+	}
+
+	for _, tt := range tests {
+		listener := &MockListener{}
+		ob := NewOrderBook(listener)
+		ob.StartAuction()
+		for i, o := range tt.buys {
+			ob.Process(*NewOrder("b"+string(rune('0'+i)), Buy, DecimalBig(o[0]), DecimalBig(o[1])))
+		}
+		for i, o := range tt.sells {
+			ob.Process(*NewOrder("s"+string(rune('0'+i)), Sell, DecimalBig(o[0]), DecimalBig(o[1])))
+		}
+
+		price, volume := ob.Uncross(DecimalBig(tt.ref))
+		if price == nil || price.Cmp(DecimalBig(tt.price)) != 0 || volume.Cmp(DecimalBig(tt.volume)) != 0 {
+			t.Fatalf("%s: unexpected equilibrium (have: %v @ %v, want: %s @ %s)", tt.name, volume, price, tt.volume, tt.price)
+		}
+		var traded int64
+		for _, trade := range listener.Trades {
+			if trade.Price != price.Val {
+				t.Fatalf("%s: all trades should execute at the equilibrium price", tt.name)
+			}
+			traded += trade.Amount
+		}
+		if traded != volume.Val {
+			t.Fatalf("%s: traded %d, want %d", tt.name, traded, volume.Val)
+		}
+		if best, ask := ob.bestPrice(Sell, ob.BuyTree), ob.bestPrice(Buy, ob.SellTree); best != nil && ask != nil && best.Cmp(ask) != -1 {
+			t.Fatalf("%s: book should not be crossed after uncross (bid %s, ask %s)", tt.name, best, ask)
+		}
+	}
+}
+
+func TestUncrossPriceTimePriority(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.StartAuction()
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b3", Buy, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("2.0"), DecimalBig("99.0")))
+
+	price, _ := ob.Uncross(DecimalBig("100.0"))
+	if price.Cmp(DecimalBig("100.0")) != 0 || len(listener.Trades) != 2 {
+		t.Fatalf("unexpected uncross (have: %v, %+v)", price, listener.Trades)
+	}
+	if listener.Trades[0].TakerID != "b3" || listener.Trades[1].TakerID != "b1" {
+		t.Fatalf("allocation should follow price-time priority (have: %+v)", listener.Trades)
+	}
+	if _, ok := ob.lookup("b2"); !ok || ob.InAuction() {
+		t.Fatal("unfilled orders should rest and continuous trading resume")
+	}
+
+	// 恢复连续撮合
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.Trades) != 3 || listener.Trades[2].MakerID != "b2" {
+		t.Fatal("book should match continuously after uncross")
+	}
+}
+
+func TestUncrossIceberg(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.StartAuction()
+	ob.Process(newIceberg("ice", Sell, "5.0", "1.0", "100.0"))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("3.0"), DecimalBig("100.0")))
+
+	_, volume := ob.Uncross(nil)
+	if volume.Cmp(DecimalBig("3.0")) != 0 {
+		t.Fatalf("hidden reserve should count towards auction volume (have: %s)", volume)
+	}
+	if ice := ob.Arena.Get(indexOf(ob, "ice")); ice.remaining().Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatalf("unexpected iceberg remainder (have: %s)", ice.remaining())
+	}
+}
+
+func TestUncrossNoCross(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.StartAuction()
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("99.0")))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	if price, _ := ob.Uncross(nil); price != nil || len(listener.Trades) != 0 {
+		t.Fatal("uncrossed book should not trade")
+	}
+}
+
+func TestUncrossCancelsOCOSiblings(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.StartAuction()
+	if err := ob.ProcessOCO("g",
+		GroupLeg{Order: *NewOrder("a", Sell, DecimalBig("1.0"), DecimalBig("100.0"))},
+		GroupLeg{Order: *NewOrder("b", Sell, DecimalBig("1.0"), DecimalBig("100.0"))},
+	); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("2.0"), DecimalBig("100.0")))
+
+	price, volume := ob.Uncross(nil)
+	if price == nil || volume.Cmp(DecimalBig("1.0")) != 0 || len(listener.Trades) != 1 || listener.Trades[0].MakerID != "a" {
+		t.Fatalf("only one OCO leg should fill (have: %v, %+v)", volume, listener.Trades)
+	}
+	if !hasID(listener.Cancelled, "b") {
+		t.Fatal("sibling leg should be cancelled")
+	}
+	if rest := ob.Arena.Get(indexOf(ob, "b1")); rest.Amount.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("buy remainder should rest (have: %s)", rest.Amount)
+	}
+}
+
+func TestUncrossSelfTradePrevention(t *testing.T) {
+	var tests = []struct {
+		mode      STPMode
+		trades    int
+		cancelled []string
+		resting   []string
+	}{
+		{STPCancelNewest, 0, []string{"b1"}, []string{"s1", "s2"}},
+		{STPCancelOldest, 1, []string{"s1"}, []string{"b1"}},
+		{STPCancelBoth, 0, []string{"b1", "s1"}, []string{"s2"}},
+		{STPDecrementAndCancel, 1, []string{"s1"}, nil},
+	}
+	for _, tt := range tests {
+		listener := &stpListener{}
+		ob := NewOrderBook(listener)
+		ob.StartAuction()
+		ob.Process(newAccountOrder("s1", Sell, "1.0", "100.0", "alice", ""))
+		ob.Process(newAccountOrder("s2", Sell, "1.0", "101.0", "bob", ""))
+		ob.Process(newAccountOrder("b1", Buy, "2.0", "101.0", "alice", tt.mode))
+
+		ob.Uncross(nil)
+		if len(listener.Prevented) != 1 || listener.Prevented[0] != "s1/b1" {
+			t.Fatalf("%s: self-trade should be prevented (have: %v)", tt.mode, listener.Prevented)
+		}
+		for _, trade := range listener.Trades {
+			if trade.MakerID == "s1" {
+				t.Fatalf("%s: same-account orders should not trade (have: %+v)", tt.mode, listener.Trades)
+			}
+		}
+		if len(listener.Trades) != tt.trades {
+			t.Fatalf("%s: unexpected trades (have: %+v)", tt.mode, listener.Trades)
+		}
+		for _, id := range tt.cancelled {
+			if !hasID(listener.Cancelled, id) {
+				t.Fatalf("%s: %s should be cancelled (have: %v)", tt.mode, id, listener.Cancelled)
+			}
+		}
+		for _, id := range tt.resting {
+			if _, ok := ob.lookup(id); !ok {
+				t.Fatalf("%s: %s should keep resting", tt.mode, id)
+			}
+		}
+	}
+}
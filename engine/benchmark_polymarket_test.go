@@ -128,3 +128,43 @@ func BenchmarkPolymarketSimulation(b *testing.B) {
 func polyDecimal(s string) (*util.StandardBigDecimal, error) {
 	return util.NewDecimalFromString(s)
 }
+
+// noopBinaryListener 压测用的空回调
+type noopBinaryListener struct{}
+
+func (noopBinaryListener) OnTrade(makerOrderID, takerOrderID string, side Side, price, amount int64) {}
+func (noopBinaryListener) OnOrderCancelled(orderID string)                                          {}
+func (noopBinaryListener) OnOrderAccepted(orderID string)                                           {}
+func (noopBinaryListener) OnBinaryTrade(makerOrderID, takerOrderID string, match MatchType, takerOutcome Outcome, takerPrice, amount int64) {
+}
+
+// BenchmarkPolymarketBinary 模拟 YES/NO 双订单簿：订单随机进入任一结果代币，互补撮合让两边的流动性互相成交
+func BenchmarkPolymarketBinary(b *testing.B) {
+	m := NewBinaryMarket(noopBinaryListener{})
+	r := rand.New(rand.NewSource(42))
+	amountDec, _ := polyDecimal("10.0")
+
+	submit := func(id string, fairPrice float64) {
+		outcome := OutcomeYes
+		if r.Intn(2) == 0 {
+			// NO 的公允价为 1 - YES 公允价
+			outcome, fairPrice = OutcomeNo, 1-fairPrice
+		}
+		isBuy := r.Intn(2) == 0
+		side := Buy
+		if !isBuy {
+			side = Sell
+		}
+		priceDec, _ := polyDecimal(generatePolyPrice(r, fairPrice, isBuy))
+		m.Process(outcome, *NewOrder(id, side, amountDec.Clone(), priceDec))
+	}
+
+	for i := 0; i < 10000; i++ {
+		submit(fmt.Sprintf("pre-%d", i), PolyBasePrice)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		submit(fmt.Sprintf("bench-%d", i), PolyBasePrice)
+	}
+}
@@ -0,0 +1,404 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// Outcome 二元市场的结果代币
+type Outcome string
+
+// OutcomeYes 与 OutcomeNo 互补：一份 YES 加一份 NO 始终可兑换 1 单位抵押品
+const (
+	OutcomeYes Outcome = "yes"
+	OutcomeNo  Outcome = "no"
+)
+
+// MatchType 互补撮合方式
+type MatchType string
+
+const (
+	// MatchMint YES 买单与 NO 买单成交：双方出资合计 1，铸造一组完整份额后分别交付
+	MatchMint MatchType = "mint"
+	// MatchMerge YES 卖单与 NO 卖单成交：双方交出的份额合并赎回 1 单位抵押品后按价格分配
+	MatchMerge MatchType = "merge"
+)
+
+// BinaryListener 二元市场的撮合事件回调
+// 同一结果代币的买卖成交（直接撮合）仍通过 OnTrade 上报，互补撮合通过 OnBinaryTrade 上报
+type BinaryListener interface {
+	MatchingListener
+
+	// OnBinaryTrade 当 Taker 与另一结果代币订单簿中的同方向 Maker 互补成交时触发
+	// takerOutcome 为 Taker 所在的结果代币，Taker 成交价为 takerPrice，Maker 成交价为 1 - takerPrice
+	OnBinaryTrade(makerOrderID, takerOrderID string, match MatchType, takerOutcome Outcome, takerPrice, amount int64)
+}
+
+// BinaryMarket 二元预测市场：YES 与 NO 两个订单簿
+// 价格 p 的 YES 买单既可与 YES 卖单成交，也可与价格不低于 1-p 的 NO 买单互补成交（mint）；
+// YES 卖单同理可与价格不高于 1-p 的 NO 卖单互补成交（merge）。Taker 按对其最优的价格在两条路径间逐档撮合，
+// 同价时优先直接撮合。新订单须通过 BinaryMarket 提交；直接提交到单个订单簿的订单只参与该订单簿内的撮合（成交仍计入账户持仓）
+type BinaryMarket struct {
+	Yes       *OrderBook
+	No        *OrderBook
+	listener  BinaryListener
+	positions map[string]*AccountSettlement // 账户 ID -> 成交持仓
+	ledgerMu  sync.Mutex                    // 保护 positions（单个订单簿上的成交也会记账）
+	group     *MarketGroup                  // 所属的多结果市场组（neg-risk），独立市场为 nil
+	index     int                           // 在市场组中的结果序号
+}
+
+// NewBinaryMarket 返回二元市场实例，两个订单簿共用 listener 与构造选项
+// 默认价格范围为 (0, 1)，可通过各订单簿的 SetInstrumentSpec 调整
+func NewBinaryMarket(listener BinaryListener, opts ...BookOption) *BinaryMarket {
+	m := &BinaryMarket{
+		Yes:       NewOrderBook(listener, opts...),
+		No:        NewOrderBook(listener, opts...),
+		listener:  listener,
+		positions: make(map[string]*AccountSettlement),
+	}
+	// 两个订单簿的成交 ID 以结果区分
+	m.Yes.bookID = subBookID(m.Yes.bookID, string(OutcomeYes))
+	m.No.bookID = subBookID(m.No.bookID, string(OutcomeNo))
+	for _, ob := range []*OrderBook{m.Yes, m.No} {
+		ob.spec.MinPrice = &util.StandardBigDecimal{Val: 1}
+		ob.spec.MaxPrice = &util.StandardBigDecimal{Val: util.SCALE - 1}
+	}
+	m.Yes.fillHook = func(makerAccount, takerAccount string, side Side, price, amount int64) {
+		m.recordTrade(OutcomeYes, makerAccount, takerAccount, side, price, amount)
+	}
+	m.No.fillHook = func(makerAccount, takerAccount string, side Side, price, amount int64) {
+		m.recordTrade(OutcomeNo, makerAccount, takerAccount, side, price, amount)
+	}
+	return m
+}
+
+// subBookID 返回下级订单簿的标识：未设置 WithBookID 时为 name，否则为 "<标识>/<name>"
+func subBookID(id, name string) string {
+	if id == "" {
+		return name
+	}
+	return id + "/" + name
+}
+
+// Book 返回结果代币对应的订单簿
+func (m *BinaryMarket) Book(outcome Outcome) *OrderBook {
+	own, _ := m.books(outcome)
+	return own
+}
+
+// Process 提交结果代币 outcome 的限价单
+// 条件单进入该结果代币的条件单簿，触发后只在该订单簿内撮合
+func (m *BinaryMarket) Process(outcome Outcome, order Order) {
+	if m.group != nil {
+		// 跨结果路由会访问组内其他市场的订单簿，由市场组统一加锁
+		m.group.Process(m.index, outcome, order)
+		return
+	}
+	own, other := m.books(outcome)
+	// 固定加锁顺序（先 YES 后 NO），与单个订单簿上的操作互斥
+	m.Yes.mutex.Lock()
+	defer m.Yes.mutex.Unlock()
+	m.No.mutex.Lock()
+	defer m.No.mutex.Unlock()
+	defer m.No.releaseIDs()
+	defer m.Yes.releaseIDs()
+
+	if order.StopPrice != nil {
+		own.addStopOrder(order, false)
+	} else {
+		m.process(outcome, order)
+	}
+	own.triggerStops()
+	other.triggerStops()
+}
+
+// books 返回结果代币所在的订单簿及其互补订单簿
+func (m *BinaryMarket) books(outcome Outcome) (own, other *OrderBook) {
+	if outcome == OutcomeNo {
+		return m.No, m.Yes
+	}
+	return m.Yes, m.No
+}
+
+// complement 返回互补的结果代币
+func (m *BinaryMarket) complement(outcome Outcome) Outcome {
+	if outcome == OutcomeNo {
+		return OutcomeYes
+	}
+	return OutcomeNo
+}
+
+// process 互补撮合限价单（调用方持有相关订单簿的锁）
+// 属于市场组时，NO 订单还会与组内其他结果的 YES 订单簿按跨结果路由撮合
+func (m *BinaryMarket) process(outcome Outcome, order Order) {
+	own, other := m.books(outcome)
+	order.enter()
+	if own.rejectDuplicate(&order) || own.rejectInvalid(&order, false) {
+		return
+	}
+	if !m.continuous() {
+		// 任一订单簿不在连续撮合阶段时不做互补撮合
+		own.process(order)
+		return
+	}
+
+	tree, add := own.SellTree, own.addBuyOrder
+	if order.Type == Sell {
+		tree, add = own.BuyTree, own.addSellOrder
+	}
+
+	if order.PostOnly == PostOnlyReject || order.PostOnly == PostOnlyReprice {
+		// post-only 订单同样不能吃掉互补订单簿（及跨结果路由）的流动性，之后按普通 post-only 订单处理
+		alt := bestOf(order.Type, m.impliedPrice(other, order.Type), m.routePrice(outcome, &order))
+		if alt != nil && crosses(&order, alt) {
+			if order.PostOnly == PostOnlyReject {
+				own.rejectOrder(&order, RejectPostOnlyWouldTake)
+				return
+			}
+			if order.Type == Buy {
+				order.Price = alt.Sub(own.spec.TickSize)
+			} else {
+				order.Price = alt.Add(own.spec.TickSize)
+			}
+			if order.Price.Cmp(decimalZero) != 1 {
+				own.rejectOrder(&order, RejectPostOnlyWouldTake)
+				return
+			}
+		}
+		if m.group != nil && !m.group.bidsConsistent(m.index, outcome, &order) {
+			own.rejectOrder(&order, RejectBidsExceedOne)
+			return
+		}
+		own.process(order)
+		return
+	}
+
+	rests := true
+	switch order.TimeInForce {
+	case IOC:
+		add, rests = own.cancelRemainder, false
+	case FOK:
+		if m.unfilled(outcome, &order, tree) > 0 {
+			own.cancelWithReason(&order, CancelFillOrKill)
+			return
+		}
+		add, rests = own.cancelRemainder, false
+	}
+
+	limit := order.Price
+loop:
+	for order.Amount.Cmp(decimalZero) == 1 && m.continuous() {
+		direct := crossing(&order, own.bestPrice(order.Type, tree))
+		implied := crossing(&order, m.impliedPrice(other, order.Type))
+		alt := bestOf(order.Type, implied, crossing(&order, m.routePrice(outcome, &order)))
+		switch {
+		case direct == nil && alt == nil:
+			break loop
+		case direct != nil && (alt == nil || crosses(&Order{Type: order.Type, Price: alt}, direct)):
+			// 直接撮合更优：以其他路径的最优价为上限扫本订单簿，剩余部分留待下一轮比较
+			if alt != nil {
+				order.Price = alt
+			}
+			own.commonProcess(&order, tree, func(Order) {})
+			order.Price = limit
+		case alt == implied:
+			if !m.matchComplement(outcome, &order) {
+				break loop
+			}
+		default:
+			if !m.group.fillRoute(m.index, &order) {
+				break loop
+			}
+		}
+	}
+	order.Price = limit
+
+	if order.Amount.Cmp(decimalZero) == 1 {
+		if rests && m.group != nil && !m.group.bidsConsistent(m.index, outcome, &order) {
+			own.cancelWithReason(&order, CancelBidsExceedOne)
+			return
+		}
+		add(order)
+	}
+}
+
+// continuous 判断撮合涉及的订单簿是否都处于连续撮合阶段
+func (m *BinaryMarket) continuous() bool {
+	if m.group != nil {
+		return m.group.continuous()
+	}
+	return m.Yes.state == StateContinuous && m.No.state == StateContinuous
+}
+
+// routePrice 返回市场组内 NO 订单跨结果路由的价格，不属于市场组或非 NO 订单时返回 nil
+func (m *BinaryMarket) routePrice(outcome Outcome, order *Order) *util.StandardBigDecimal {
+	if m.group == nil || outcome != OutcomeNo {
+		return nil
+	}
+	return m.group.routePrice(m.index, order)
+}
+
+// matchComplement 与互补订单簿最优档位的首个 Maker 成交（调用方已检查价格）
+// 返回 false 表示 Taker 已停止撮合（被自成交防护撤销或触发熔断）
+func (m *BinaryMarket) matchComplement(outcome Outcome, order *Order) bool {
+	own, other := m.books(outcome)
+	tree, match := other.BuyTree, MatchMint
+	if order.Type == Sell {
+		tree, match = other.SellTree, MatchMerge
+	}
+	node := other.bestNode(tree, oppositeSide(order.Type))
+	idx := node.Head
+	maker := other.Arena.Get(idx)
+	takerPrice := &util.StandardBigDecimal{Val: util.SCALE - maker.Price.Val}
+	if own.breachesBand(takerPrice) || other.breachesBand(maker.Price) {
+		return false
+	}
+
+	switch {
+	case maker.siblingDone():
+		other.cancelMaker(node, idx, CancelOrderGroup)
+	case isSelfTrade(order, maker):
+		if other.preventSelfTrade(order, node, idx) {
+			return false
+		}
+	default:
+		fill := order.Amount.Val
+		if maker.Amount.Val < fill {
+			fill = maker.Amount.Val
+		}
+		m.onBinaryTrade(outcome, maker, order, match, takerPrice.Val, fill)
+		order.Amount.Val -= fill
+		other.fillResting(node, idx, fill)
+		return true
+	}
+	if node.Count == 0 {
+		other.removeOrder(maker)
+	}
+	return true
+}
+
+// onBinaryTrade 记录两个订单簿的最新成交价并触发互补成交事件（及手续费）
+func (m *BinaryMarket) onBinaryTrade(outcome Outcome, maker, taker *Order, match MatchType, takerPrice, amount int64) {
+	own, other := m.books(outcome)
+	own.lastPrice = takerPrice
+	other.lastPrice = util.SCALE - takerPrice
+	maker.fill(util.SCALE-takerPrice, amount)
+	taker.fill(takerPrice, amount)
+	makerOrderID, takerOrderID := maker.ID, taker.ID
+	m.listener.OnBinaryTrade(makerOrderID, takerOrderID, match, outcome, takerPrice, amount)
+	fees := chargeFees(other, own, maker, taker, util.SCALE-takerPrice, takerPrice, amount)
+	// 成交事件在双方订单簿的事件流中各记录一次（共用 Taker 订单簿的成交 ID），执行报告由各自订单簿生成
+	own.seq++
+	id := own.tradeID()
+	own.publishTrade(id, maker, taker, match, util.SCALE-takerPrice, takerPrice, amount, fees, false, true)
+	other.seq++
+	other.publishTrade(id, maker, taker, match, util.SCALE-takerPrice, takerPrice, amount, fees, true, false)
+
+	side := Buy
+	if match == MatchMerge {
+		side = Sell
+	}
+	m.ledgerMu.Lock()
+	m.recordFill(taker.Account, outcome, side, takerPrice, amount)
+	m.recordFill(maker.Account, m.complement(outcome), side, util.SCALE-takerPrice, amount)
+	m.ledgerMu.Unlock()
+	if len(other.groups.byOrder) > 0 {
+		other.groups.onTrade(maker, amount)
+	}
+}
+
+// impliedPrice 返回互补订单簿同方向最优价对应的本结果代币价格（1 - 最优价），无挂单时返回 nil
+func (m *BinaryMarket) impliedPrice(other *OrderBook, side Side) *util.StandardBigDecimal {
+	tree := other.BuyTree
+	if side == Sell {
+		tree = other.SellTree
+	}
+	best := other.bestPrice(oppositeSide(side), tree)
+	if best == nil {
+		return nil
+	}
+	return &util.StandardBigDecimal{Val: util.SCALE - best.Val}
+}
+
+// unfilled 返回在价格范围内各路径可成交量都用完后订单仍未满足的数量（FOK 预检查）
+// 各档位的 Maker 按 canFill 的规则过滤（订单组兄弟订单、自成交、价格带），Taker 会被自成交防护撤销时返回全部数量
+func (m *BinaryMarket) unfilled(outcome Outcome, order *Order, tree PriceLevels) int64 {
+	own, other := m.books(outcome)
+	remaining := order.Amount.Val
+	stopped := false
+	var groups []*orderGroup
+	tree.Walk(order.Type == Buy, func(price int64, node *OrderNode) bool {
+		if !crosses(order, own.Arena.Get(node.Head).Price) || own.outsideBands(price) {
+			return false
+		}
+		liquidity, ok := own.levelLiquidity(order, node, remaining, &groups)
+		remaining -= liquidity
+		stopped = !ok
+		return ok && remaining > 0
+	})
+	if stopped {
+		return order.Amount.Val
+	}
+	if remaining <= 0 {
+		return 0
+	}
+
+	sameSide := other.BuyTree
+	if order.Type == Sell {
+		sameSide = other.SellTree
+	}
+	sameSide.Walk(order.Type == Sell, func(price int64, node *OrderNode) bool {
+		implied := &util.StandardBigDecimal{Val: util.SCALE - price}
+		if !crosses(order, implied) || own.outsideBands(implied.Val) || other.outsideBands(price) {
+			return false
+		}
+		liquidity, ok := other.levelLiquidity(order, node, remaining, &groups)
+		remaining -= liquidity
+		stopped = !ok
+		return ok && remaining > 0
+	})
+	if stopped {
+		return order.Amount.Val
+	}
+	if remaining > 0 && m.group != nil && outcome == OutcomeNo {
+		remaining -= m.group.routeDepth(m.index, order, remaining)
+	}
+	return remaining
+}
+
+// crosses 判断订单能否以 price 成交（买单 price 不高于限价，卖单 price 不低于限价）
+func crosses(order *Order, price *util.StandardBigDecimal) bool {
+	if order.Type == Buy {
+		return price.Cmp(order.Price) != 1
+	}
+	return price.Cmp(order.Price) != -1
+}
+
+// crossing 价格可成交时原样返回，否则（或 price 为 nil 时）返回 nil
+func crossing(order *Order, price *util.StandardBigDecimal) *util.StandardBigDecimal {
+	if price == nil || !crosses(order, price) {
+		return nil
+	}
+	return price
+}
+
+// bestOf 返回对 side 方向 Taker 更优的价格（同价时返回 a），nil 表示不可用
+func bestOf(side Side, a, b *util.StandardBigDecimal) *util.StandardBigDecimal {
+	if a == nil {
+		return b
+	}
+	if b == nil || crosses(&Order{Type: side, Price: b}, a) {
+		return a
+	}
+	return b
+}
+
+// oppositeSide 返回相反方向
+func oppositeSide(side Side) Side {
+	if side == Buy {
+		return Sell
+	}
+	return Buy
+}
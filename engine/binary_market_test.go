@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"testing"
+)
+
+type binaryTrade struct {
+	MockTrade
+	Match   MatchType
+	Outcome Outcome
+}
+
+type binaryListener struct {
+	rejectListener
+	Binary []binaryTrade
+}
+
+func (l *binaryListener) OnBinaryTrade(makerID, takerID string, match MatchType, outcome Outcome, price, amount int64) {
+	l.Binary = append(l.Binary, binaryTrade{MockTrade{makerID, takerID, price, amount}, match, outcome})
+}
+
+func TestBinaryMint(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+	m.Process(OutcomeNo, *NewOrder("no1", Buy, DecimalBig("10.0"), DecimalBig("0.4")))
+	m.Process(OutcomeNo, *NewOrder("no2", Buy, DecimalBig("10.0"), DecimalBig("0.35")))
+
+	// YES 买 0.62：可与 NO 0.4 买单互补成交（YES 成交价 0.6），NO 0.35 对应 0.65 不可成交
+	m.Process(OutcomeYes, *NewOrder("yes1", Buy, DecimalBig("15.0"), DecimalBig("0.62")))
+	if len(listener.Binary) != 1 {
+		t.Fatalf("expected one mint (have: %+v)", listener.Binary)
+	}
+	trade := listener.Binary[0]
+	if trade.Match != MatchMint || trade.Outcome != OutcomeYes || trade.MakerID != "no1" || trade.Price != DecimalBig("0.6").Val || trade.Amount != DecimalBig("10.0").Val {
+		t.Fatalf("unexpected mint %+v", trade)
+	}
+	if _, ok := m.No.lookup("no1"); ok {
+		t.Fatal("filled complementary maker should leave the book")
+	}
+	rest := m.Yes.Arena.Get(indexOf(m.Yes, "yes1"))
+	if rest.Amount.Cmp(DecimalBig("5.0")) != 0 || m.Yes.lastPrice != DecimalBig("0.6").Val || m.No.lastPrice != DecimalBig("0.4").Val {
+		t.Fatal("taker remainder should rest and both last prices should update")
+	}
+}
+
+func TestBinaryMerge(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+	m.Process(OutcomeYes, *NewOrder("yes1", Sell, DecimalBig("5.0"), DecimalBig("0.7")))
+
+	// NO 卖 0.25：YES 0.7 卖单对应 NO 0.3，合并赎回
+	m.Process(OutcomeNo, *NewOrder("no1", Sell, DecimalBig("5.0"), DecimalBig("0.25")))
+	if len(listener.Binary) != 1 || listener.Binary[0].Match != MatchMerge || listener.Binary[0].Price != DecimalBig("0.3").Val {
+		t.Fatalf("expected merge at 0.3 (have: %+v)", listener.Binary)
+	}
+	if m.Yes.orders.size() != 0 || m.No.orders.size() != 0 {
+		t.Fatal("both sides should be fully filled")
+	}
+}
+
+func TestBinaryBestPath(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+	m.Process(OutcomeYes, *NewOrder("ask55", Sell, DecimalBig("5.0"), DecimalBig("0.55")))
+	m.Process(OutcomeYes, *NewOrder("ask60", Sell, DecimalBig("5.0"), DecimalBig("0.6")))
+	m.Process(OutcomeNo, *NewOrder("no42", Buy, DecimalBig("5.0"), DecimalBig("0.42")))
+	m.Process(OutcomeNo, *NewOrder("no40", Buy, DecimalBig("5.0"), DecimalBig("0.4")))
+
+	// 依次成交：直接 0.55、互补 0.58、同价时优先直接 0.6、互补 0.6
+	m.Process(OutcomeYes, *NewOrder("taker", Buy, DecimalBig("20.0"), DecimalBig("0.6")))
+	if len(listener.Trades) != 2 || listener.Trades[0].MakerID != "ask55" || listener.Trades[1].MakerID != "ask60" {
+		t.Fatalf("unexpected direct trades %+v", listener.Trades)
+	}
+	if len(listener.Binary) != 2 || listener.Binary[0].MakerID != "no42" || listener.Binary[1].MakerID != "no40" {
+		t.Fatalf("unexpected mints %+v", listener.Binary)
+	}
+	if _, ok := m.Yes.lookup("taker"); ok {
+		t.Fatal("taker should be fully filled")
+	}
+}
+
+func TestBinaryFOKAndPostOnly(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+	m.Process(OutcomeYes, *NewOrder("ask", Sell, DecimalBig("5.0"), DecimalBig("0.5")))
+	m.Process(OutcomeNo, *NewOrder("no", Buy, DecimalBig("5.0"), DecimalBig("0.5")))
+
+	fok := NewOrder("fok", Buy, DecimalBig("11.0"), DecimalBig("0.5"))
+	fok.TimeInForce = FOK
+	m.Process(OutcomeYes, *fok)
+	if len(listener.Trades)+len(listener.Binary) != 0 {
+		t.Fatal("FOK without enough combined depth should not trade")
+	}
+
+	post := NewOrder("post", Sell, DecimalBig("1.0"), DecimalBig("0.45"))
+	post.PostOnly = PostOnlyReject
+	m.Process(OutcomeNo, *post)
+	if listener.Rejected["post"] != RejectPostOnlyWouldTake {
+		t.Fatal("post-only should not take complementary liquidity")
+	}
+
+	fok = NewOrder("fok2", Buy, DecimalBig("10.0"), DecimalBig("0.5"))
+	fok.TimeInForce = FOK
+	m.Process(OutcomeYes, *fok)
+	if len(listener.Trades) != 1 || len(listener.Binary) != 1 {
+		t.Fatalf("FOK should fill across both paths (have: %+v, %+v)", listener.Trades, listener.Binary)
+	}
+
+	m.Process(OutcomeYes, *NewOrder("bad", Buy, DecimalBig("1.0"), DecimalBig("1.0")))
+	if listener.Rejected["bad"] != RejectPriceRange {
+		t.Fatal("binary prices should stay inside (0, 1)")
+	}
+}
+
+func TestBinaryFOKSkipsSelfTradeDepth(t *testing.T) {
+	for _, outcome := range []Outcome{OutcomeYes, OutcomeNo} {
+		listener := &binaryListener{}
+		m := NewBinaryMarket(listener)
+		m.Process(OutcomeYes, newBinaryOrder("ask", "x", Sell, "5.0", "0.5"))
+		// 同账户的流动性位于本订单簿或互补订单簿，撮合时会被 STP 撤销，不计入 FOK 深度
+		if outcome == OutcomeYes {
+			m.Process(OutcomeYes, newBinaryOrder("own", "me", Sell, "5.0", "0.5"))
+		} else {
+			m.Process(OutcomeNo, newBinaryOrder("own", "me", Buy, "5.0", "0.5"))
+		}
+
+		fok := newBinaryOrder("fok", "me", Buy, "10.0", "0.5")
+		fok.TimeInForce = FOK
+		m.Process(OutcomeYes, fok)
+		if len(listener.Trades)+len(listener.Binary) != 0 {
+			t.Fatalf("%s: FOK should not partly fill against self-trade depth (have: %+v, %+v)", outcome, listener.Trades, listener.Binary)
+		}
+	}
+}
+
+func TestBinaryFOKCountsIcebergReserve(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+	m.Process(OutcomeYes, newIceberg("ask", Sell, "4.0", "1.0", "0.5"))
+	m.Process(OutcomeNo, newIceberg("no", Buy, "4.0", "1.0", "0.5"))
+
+	fok := NewOrder("fok", Buy, DecimalBig("8.0"), DecimalBig("0.5"))
+	fok.TimeInForce = FOK
+	m.Process(OutcomeYes, *fok)
+	if _, ok := m.Yes.lookup("ask"); ok {
+		t.Fatalf("FOK should fill against both iceberg reserves (have: %+v, %+v)", listener.Trades, listener.Binary)
+	}
+	if _, ok := m.No.lookup("no"); ok {
+		t.Fatal("complementary iceberg should be fully minted")
+	}
+}
@@ -1,35 +1,54 @@
 package engine
 
-// CancelOrder 从订单簿移除该订单并返回被移除的订单
+// CancelOrder 从订单簿（或条件单簿）移除该订单并返回被移除的订单
 func (ob *OrderBook) CancelOrder(id string) *Order {
 	ob.mutex.Lock()
 	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
 
-	idx, ok := ob.orders[id]
+	eid, _ := ob.ids.lookup(id)
+	idx, ok := ob.orders.get(eid)
 	if !ok {
+		// 未触发的条件单不在订单簿中，走条件单簿的撤单路径
+		if stop := ob.stops.cancel(eid); stop != nil {
+			ob.retire(eid)
+			ob.emit(EventCancelled, stop, string(CancelRequested))
+			ob.cancelGroup(stop)
+			return NewOrder(stop.ID, stop.Type, stop.Amount.Clone(), stop.Price.Clone())
+		}
 		return nil
 	}
 
 	orderInArena := ob.Arena.Get(idx)
-	// 创建副本返回
-	retOrder := NewOrder(orderInArena.ID, orderInArena.Type, orderInArena.Amount.Clone(), orderInArena.Price.Clone())
+	// 创建副本返回（冰山单返回显示与隐藏的剩余总量）
+	retOrder := NewOrder(orderInArena.ID, orderInArena.Type, orderInArena.remaining(), orderInArena.Price.Clone())
+	// removeIndex 会回收 Arena 空间，之后撤销订单组时触发的条件单可能重新分配该位置：只使用回收前的副本
+	cancelled := *orderInArena
+
+	ob.removeIndex(idx)
+	ob.emit(EventCancelled, &cancelled, string(CancelRequested))
+	ob.cancelGroup(&cancelled)
+	return retOrder
+}
 
-	if orderInArena.Node != nil {
-		node := orderInArena.Node
-		// 注意：removeOrder 会调用 Arena.Free(idx)，但数据在当前锁范围内依然可读（尚未被覆盖）
-		// 不过为了更安全，应该先从 Tree 移除（如果需要），再从 Node 移除？
-		// 不，只有 Node 空了才从 Tree 移除。
-		// 先获取必要信息
-		// nodeCount := node.Count - 1 // 预测
-		// node.removeOrder(ob.Arena, idx)
-		
-		// 或者：
+// removeIndex 将 Arena 中的挂单从价格档位、价格树及订单索引中移除（调用方持有锁）
+func (ob *OrderBook) removeIndex(idx IndexType) {
+	orderInArena := ob.Arena.Get(idx)
+	ob.forget(orderInArena)
+	if orderInArena.Node != NullIndex {
+		node := ob.Levels.Get(orderInArena.Node)
+		// removeOrder 会调用 Arena.Free(idx)，但数据在当前锁范围内依然可读（尚未被覆盖）
 		node.removeOrder(ob.Arena, idx)
 		if node.Count == 0 {
 			ob.removeOrder(orderInArena)
 		}
 	}
+}
 
-	delete(ob.orders, id)
-	return retOrder
+// cancelGroup 订单被主动撤销时撤销其所在订单组的其余腿
+func (ob *OrderBook) cancelGroup(order *Order) {
+	if ob.groups.member(order) != nil {
+		ob.groups.fire(order)
+		ob.triggerStops()
+	}
 }
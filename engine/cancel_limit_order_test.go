@@ -27,9 +27,9 @@ func TestCancelOrder(t *testing.T) {
 		}
 	}
 
-	idx := ob.orders[tests[4].input.ID]
+	idx := indexOf(ob, tests[4].input.ID)
 	orderInArena := ob.Arena.Get(idx)
-	on := orderInArena.Node
+	on := ob.Levels.Get(orderInArena.Node)
 
 	order := ob.CancelOrder("s1")
 
@@ -57,7 +57,25 @@ func TestCancelOrder(t *testing.T) {
 		t.Fatal("Order is not removed from Tree of Orderbook")
 	}
 
-	if _, ok := ob.orders[order.ID]; ok {
+	if _, ok := ob.lookup(order.ID); ok {
 		t.Fatal("Order is not removed from \"orders\" of Orderbook")
 	}
 }
+
+func TestCancelSteadyStateAllocs(t *testing.T) {
+	ob := NewOrderBook(nil)
+	rest := *NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("90.0"))
+	cycle := func() {
+		rest.Amount.Val = 1e8
+		ob.Process(rest)
+		ob.CancelOrder("b1")
+	}
+	cycle()
+	// 只允许返回给调用方的订单副本分配内存（Order 及其数量、价格）
+	if allocs := testing.AllocsPerRun(100, cycle); allocs > 3 {
+		t.Fatalf("steady-state cancel should only allocate the returned copy (have: %v allocs per cycle)", allocs)
+	}
+	if ob.BuyTree.Len() != 0 || ob.orders.size() != 0 {
+		t.Fatal("book should be empty after each cycle")
+	}
+}
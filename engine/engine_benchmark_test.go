@@ -5,13 +5,11 @@ import (
 	"math/rand"
 	"os"
 	"testing"
+
+	"github.com/goovo/matching-engine/util"
 )
 
-// BenchmarkLimitMatchSimple
-// 中文说明：
-// - 该基准测试用于评估限价单撮合的吞吐能力（TPS）
-// - 预先在订单簿中插入 b.N 笔卖单（数量与价格均相同），然后撮合 b.N 笔买单与之逐一成交
-// - 由于引擎内部存在较多 fmt 打印，为避免 IO 干扰，这里将标准输出重定向到 /dev/null
+// BenchmarkLimitMatchSimple 限价单撮合吞吐：预置 b.N 笔同价卖单，再用 b.N 笔买单逐一成交（标准输出重定向到 /dev/null）
 func BenchmarkLimitMatchSimple(b *testing.B) {
 	// 将标准输出重定向，避免 fmt 打印影响基准结果
 	devnull, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
@@ -43,11 +41,7 @@ func BenchmarkLimitMatchSimple(b *testing.B) {
 	}
 }
 
-// BenchmarkMarketMatchSimple
-// 中文说明：
-// - 该基准测试用于评估市价单撮合的吞吐能力（TPS）
-// - 预先在订单簿中插入 b.N 笔卖单，然后撮合 b.N 笔买入市价单
-// - 市价单的价格字段传入 0（不参与比较），仅按数量与最优价依次成交
+// BenchmarkMarketMatchSimple 市价单撮合吞吐：预置 b.N 笔卖单，再用 b.N 笔买入市价单按最优价依次成交
 func BenchmarkMarketMatchSimple(b *testing.B) {
 	// 将标准输出重定向，避免 fmt 打印影响基准结果
 	devnull, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
@@ -79,10 +73,7 @@ func BenchmarkMarketMatchSimple(b *testing.B) {
 	}
 }
 
-// BenchmarkCancelOrder
-// 中文说明：
-// - 该基准测试用于评估撤单性能（验证 O(1) 优化效果）
-// - 场景：在同一价格档位堆积大量订单，然后随机撤单
+// BenchmarkCancelOrder 撤单性能：同一价格档位堆积大量订单后随机撤单
 func BenchmarkCancelOrder(b *testing.B) {
 	// 重定向标准输出
 	devnull, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
@@ -118,3 +109,127 @@ func BenchmarkCancelOrder(b *testing.B) {
 		ob.CancelOrder(id)
 	}
 }
+
+// priceLevelBackends 参与对比的价格档位实现：默认的 PriceTree 与预测市场价格区间的 PriceLadder
+var priceLevelBackends = []struct {
+	name string
+	opts []BookOption
+}{
+	{"tree", nil},
+	{"ladder", []BookOption{WithPriceLadder(DecimalBig("0.001"), DecimalBig("0.999"), DecimalBig("0.001"))}},
+}
+
+// randomTick 返回 [lo, hi] 千分位区间内的随机价格（预测市场价位 0.001）
+func randomTick(r *rand.Rand, lo, hi int64) *util.StandardBigDecimal {
+	return &util.StandardBigDecimal{Val: (lo + r.Int63n(hi-lo+1)) * DecimalBig("0.001").Val}
+}
+
+// BenchmarkPriceLevelsRestCancel 对比两种价格档位实现的挂单与撤单：双边各约 1000 笔分散挂单，每次撤销最早的一笔并在随机价位挂新单
+func BenchmarkPriceLevelsRestCancel(b *testing.B) {
+	for _, backend := range priceLevelBackends {
+		b.Run(backend.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			ob := NewOrderBook(nil, backend.opts...)
+			const depth = 2000
+			orders := make([]Order, depth+b.N)
+			for i := range orders {
+				side, price := Buy, randomTick(r, 1, 499)
+				if i%2 == 1 {
+					side, price = Sell, randomTick(r, 501, 999)
+				}
+				orders[i] = *NewOrder(fmt.Sprintf("o-%d", i), side, DecimalBig("1.0"), price)
+			}
+			for i := 0; i < depth; i++ {
+				ob.Process(orders[i])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ob.CancelOrder(orders[i].ID)
+				ob.Process(orders[depth+i])
+			}
+		})
+	}
+}
+
+// BenchmarkPriceLevelsSweep 对比两种价格档位实现的扫单：每次在随机价位补一笔卖单，再用市价买单吃掉最优档位
+func BenchmarkPriceLevelsSweep(b *testing.B) {
+	for _, backend := range priceLevelBackends {
+		b.Run(backend.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			ob := NewOrderBook(nil, backend.opts...)
+			const depth = 1000
+			sells := make([]Order, depth+b.N)
+			buys := make([]Order, b.N)
+			for i := range sells {
+				sells[i] = *NewOrder(fmt.Sprintf("s-%d", i), Sell, DecimalBig("1.0"), randomTick(r, 1, 999))
+			}
+			for i := range buys {
+				buys[i] = *NewOrder(fmt.Sprintf("b-%d", i), Buy, DecimalBig("1.0"), DecimalBig("0"))
+			}
+			for i := 0; i < depth; i++ {
+				ob.Process(sells[i])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ob.Process(sells[depth+i])
+				ob.ProcessMarket(buys[i])
+			}
+		})
+	}
+}
+
+// BenchmarkOrderIndex 对比按字符串 ID 的 Go map 与订单 ID 驻留表 + 引擎 ID 下标表：
+// 保持约 2000 笔挂单，每次按字符串 ID 挂一笔新单并让最早的一笔离开索引（驻留表按引擎 ID 删除，不再求散列）
+func BenchmarkOrderIndex(b *testing.B) {
+	const depth = 2000
+	names := func(n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("client-order-%d", i)
+		}
+		return ids
+	}
+
+	b.Run("map", func(b *testing.B) {
+		ids := names(depth + b.N)
+		index := make(map[string]IndexType)
+		for i := 0; i < depth; i++ {
+			index[ids[i]] = IndexType(i)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			index[ids[depth+i]] = IndexType(i)
+			delete(index, ids[i])
+		}
+	})
+
+	b.Run("interned", func(b *testing.B) {
+		ids := names(depth + b.N)
+		table := newOrderIDs()
+		var orders orderTable
+		eids := make([]uint64, depth+b.N)
+		for i := 0; i < depth; i++ {
+			eids[i] = table.intern(ids[i])
+			orders.set(eids[i], IndexType(i))
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			eids[depth+i] = table.intern(ids[depth+i])
+			orders.set(eids[depth+i], IndexType(i))
+			orders.del(eids[i])
+			table.release(eids[i])
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventType 订单簿事件类型
+type EventType string
+
+const (
+	// EventAccepted 订单进入订单簿（OnOrderAccepted）
+	EventAccepted EventType = "accepted"
+	// EventCancelled 订单（剩余部分）被撤销（OnOrderCancelled，以及不触发回调的 CancelOrder 主动撤单）
+	EventCancelled EventType = "cancelled"
+	// EventRejected 订单在撮合前被拒绝（OnOrderRejected）
+	EventRejected EventType = "rejected"
+	// EventTrade 成交（OnTrade / OnBinaryTrade）
+	EventTrade EventType = "trade"
+	// EventStopAccepted 条件单进入条件单簿
+	EventStopAccepted EventType = "stop_accepted"
+	// EventStopTriggered 条件单被触发
+	EventStopTriggered EventType = "stop_triggered"
+	// EventAmended 挂单被 AmendOrder 修改（原地减量，或撤单重下后重新撮合之前）
+	EventAmended EventType = "amended"
+	// EventStateChanged 交易状态变化
+	EventStateChanged EventType = "state_changed"
+)
+
+// Event 订单簿事件记录：同一订单簿的事件序号从 1 开始连续递增，消费方可据此去重、排序并发现丢失的事件
+type Event struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	Type    EventType `json:"type"`
+	OrderID string    `json:"order_id,omitempty"`
+	// 引擎订单 ID，仅在订单挂单或未触发期间唯一（不序列化）
+	EngineID uint64       `json:"-"`
+	Reason   string       `json:"reason,omitempty"` // 撤单、拒单或交易状态变化原因
+	State    TradingState `json:"state,omitempty"`  // 交易状态变化后的状态
+	Trade    *Trade       `json:"trade,omitempty"`
+}
+
+// LastSeq 返回订单簿最近一个事件的序号（尚无事件时为 0）
+func (ob *OrderBook) LastSeq() uint64 {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	return ob.seq
+}
+
+// emit 为订单事件分配序号并通知 EventListener，受理、撤单、拒单与修改同时生成执行报告（调用方持有锁）
+func (ob *OrderBook) emit(eventType EventType, order *Order, reason string) {
+	ob.seq++
+	if ob.eventListener == nil && ob.execListener == nil {
+		return
+	}
+	now := ob.clock()
+	if ob.eventListener != nil {
+		ob.eventListener.OnEvent(Event{
+			Seq:      ob.seq,
+			Time:     now,
+			Type:     eventType,
+			OrderID:  order.ID,
+			EngineID: order.EID,
+			Reason:   reason,
+		})
+	}
+	if ob.execListener == nil {
+		return
+	}
+	var execType ExecType
+	switch eventType {
+	case EventAccepted:
+		execType = ExecNew
+	case EventCancelled:
+		execType = ExecCancelled
+	case EventRejected:
+		execType = ExecRejected
+	case EventAmended:
+		execType = ExecReplaced
+	default:
+		return
+	}
+	report := order.report(execType, reason)
+	report.Seq, report.Time = ob.seq, now
+	ob.execListener.OnExecutionReport(report)
+}
+
+// emitState 记录交易状态变化事件
+func (ob *OrderBook) emitState(state TradingState, reason StateChangeReason) {
+	ob.seq++
+	if ob.eventListener == nil {
+		return
+	}
+	ob.eventListener.OnEvent(Event{
+		Seq:    ob.seq,
+		Time:   ob.clock(),
+		Type:   EventStateChanged,
+		Reason: string(reason),
+		State:  state,
+	})
+}
+
+// emitTrade 记录成交事件并为双方各生成一份执行报告，成交 ID 见 tradeID
+// 成交记录的价格为 Taker 价格（互补撮合时 Maker 位于互补订单簿，价格为 makerPrice）
+func (ob *OrderBook) emitTrade(maker, taker *Order, match MatchType, makerPrice, takerPrice, amount int64, fees *TradeFees) {
+	ob.seq++
+	if ob.eventListener == nil && ob.execListener == nil {
+		return
+	}
+	ob.publishTrade(ob.tradeID(), maker, taker, match, makerPrice, takerPrice, amount, fees, true, true)
+}
+
+// tradeID 返回当前序号对应的成交 ID：设置了订单簿标识时为 "<标识>-<序号>"，否则为序号本身
+func (ob *OrderBook) tradeID() string {
+	if ob.bookID == "" {
+		return strconv.FormatUint(ob.seq, 10)
+	}
+	return ob.bookID + "-" + strconv.FormatUint(ob.seq, 10)
+}
+
+// publishTrade 以当前序号通知成交事件，并按 reportMaker/reportTaker 生成双方的执行报告（调用方已分配序号）
+func (ob *OrderBook) publishTrade(id string, maker, taker *Order, match MatchType, makerPrice, takerPrice, amount int64, fees *TradeFees, reportMaker, reportTaker bool) {
+	if ob.eventListener == nil && ob.execListener == nil {
+		return
+	}
+	now := ob.clock()
+	if ob.eventListener != nil {
+		trade := newTrade(maker, taker, takerPrice, amount)
+		trade.Seq = ob.seq
+		trade.ID = id
+		trade.Time = now
+		trade.Match = match
+		trade.Fees = fees
+		ob.eventListener.OnEvent(Event{
+			Seq:      trade.Seq,
+			Time:     trade.Time,
+			Type:     EventTrade,
+			OrderID:  taker.ID,
+			EngineID: taker.EID,
+			Trade:    trade,
+		})
+	}
+	if ob.execListener == nil {
+		return
+	}
+	if reportMaker {
+		ob.reportTrade(maker, true, id, makerPrice, amount, now)
+	}
+	if reportTaker {
+		ob.reportTrade(taker, false, id, takerPrice, amount, now)
+	}
+}
+
+// reportTrade 上报订单在一笔成交后的执行报告
+func (ob *OrderBook) reportTrade(order *Order, maker bool, tradeID string, price, amount int64, now time.Time) {
+	report := order.report(ExecTrade, "")
+	report.Seq, report.Time = ob.seq, now
+	report.LastPrice, report.LastAmount = price, amount
+	report.TradeID = tradeID
+	report.Maker = maker
+	ob.execListener.OnExecutionReport(report)
+}
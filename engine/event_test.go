@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type eventListener struct {
+	MockListener
+	Events []Event
+}
+
+func (l *eventListener) OnEvent(event Event) {
+	l.Events = append(l.Events, event)
+}
+
+func TestEventSequenceAndTradeRecord(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	listener := &eventListener{}
+	ob := NewOrderBook(listener, WithClock(func() time.Time { return now }))
+
+	ob.Process(newBinaryOrder("s1", "maker", Sell, "2.0", "100.0"))
+	ob.Process(newBinaryOrder("b1", "taker", Buy, "1.0", "100.0"))
+	ob.Process(newBinaryOrder("b2", "taker", Buy, "3.0", "100.0"))
+	ob.CancelOrder("b2")
+
+	types := []EventType{EventAccepted, EventTrade, EventTrade, EventAccepted, EventCancelled}
+	if len(listener.Events) != len(types) {
+		t.Fatalf("expected %d events (have: %d)", len(types), len(listener.Events))
+	}
+	for i, event := range listener.Events {
+		if event.Seq != uint64(i+1) {
+			t.Fatalf("sequence should be gap-free (event %d has seq %d)", i, event.Seq)
+		}
+		if event.Type != types[i] {
+			t.Fatalf("event %d should be %s (have: %s)", i, types[i], event.Type)
+		}
+		if !event.Time.Equal(now) {
+			t.Fatal("event time should come from the book clock")
+		}
+	}
+	if ob.LastSeq() != 5 {
+		t.Fatalf("expected last seq 5 (have: %d)", ob.LastSeq())
+	}
+
+	trade := listener.Events[1].Trade
+	if trade == nil || trade.Seq != 2 || trade.ID != "2" || trade.MakerOrderID != "s1" || trade.TakerOrderID != "b1" ||
+		trade.BuyOrderID != "b1" || trade.SellOrderID != "s1" || trade.Side != Sell || trade.Fees != nil {
+		t.Fatalf("unexpected trade record %+v", trade)
+	}
+	if trade.Price.Cmp(DecimalBig("100.0")) != 0 || trade.Amount.Cmp(DecimalBig("1.0")) != 0 || !trade.Time.Equal(now) {
+		t.Fatalf("unexpected trade price or amount %+v", trade)
+	}
+}
+
+func TestEventReasons(t *testing.T) {
+	listener := &eventListener{}
+	ob := NewOrderBook(listener)
+
+	ob.Process(newBinaryOrder("s1", "maker", Sell, "1.0", "100.0"))
+	order := newBinaryOrder("b1", "taker", Buy, "1.0", "100.0")
+	order.PostOnly = PostOnlyReject
+	ob.Process(order)
+	ob.MassCancel(MassCancelFilter{})
+	if err := ob.SetTradingState(StateHalted); err != nil {
+		t.Fatal(err)
+	}
+
+	events := listener.Events
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events (have: %d)", len(events))
+	}
+	if events[1].Type != EventRejected || events[1].OrderID != "b1" || events[1].Reason != string(RejectPostOnlyWouldTake) {
+		t.Fatalf("unexpected reject event %+v", events[1])
+	}
+	if events[2].Type != EventCancelled || events[2].OrderID != "s1" || events[2].Reason != string(CancelMassCancel) {
+		t.Fatalf("unexpected cancel event %+v", events[2])
+	}
+	if events[3].Type != EventStateChanged || events[3].State != StateHalted || events[3].Reason != string(StateChangeManual) || events[3].Seq != 4 {
+		t.Fatalf("unexpected state event %+v", events[3])
+	}
+}
+
+func TestTradeJSON(t *testing.T) {
+	trade := &Trade{
+		Seq:          7,
+		ID:           "7",
+		Time:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		MakerOrderID: "s1",
+		TakerOrderID: "b1",
+		BuyOrderID:   "b1",
+		SellOrderID:  "s1",
+		Side:         Sell,
+		Amount:       DecimalBig("1.5"),
+		Price:        DecimalBig("100.25"),
+		Fees:         &TradeFees{MakerFee: -DecimalBig("0.01").Val, MakerCurrency: "USDT", TakerFee: DecimalBig("0.3").Val, TakerCurrency: "USDT"},
+	}
+	data := trade.ToJSON()
+	expected := `{"seq":7,"id":"7","time":"2024-01-01T12:00:00Z","maker_order_id":"s1","taker_order_id":"b1","buy_order_id":"b1","sell_order_id":"s1","side":"sell","amount":"1.5","price":"100.25","maker_fee":"-0.01","maker_fee_currency":"USDT","taker_fee":"0.3","taker_fee_currency":"USDT"}`
+	if string(data) != expected {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	var decoded Trade
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Seq != 7 || decoded.Amount.Cmp(trade.Amount) != 0 || decoded.Price.Cmp(trade.Price) != 0 || *decoded.Fees != *trade.Fees || !decoded.Time.Equal(trade.Time) {
+		t.Fatalf("round trip mismatch %+v", decoded)
+	}
+	if err := decoded.FromJSON([]byte(`{"amount":"1.0","price":"x"}`)); err == nil {
+		t.Fatal("invalid price should be rejected")
+	}
+}
+
+func TestTradeIDIncludesBookID(t *testing.T) {
+	listener := &eventListener{}
+	ob := NewOrderBook(listener, WithBookID("BTC/USDT"))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if trade := listener.Events[1].Trade; trade == nil || trade.ID != "BTC/USDT-2" {
+		t.Fatalf("trade ID should be prefixed with the book ID (have: %+v)", trade)
+	}
+}
+
+type binaryEventListener struct {
+	binaryListener
+	Events []Event
+}
+
+func (l *binaryEventListener) OnEvent(event Event) {
+	l.Events = append(l.Events, event)
+}
+
+func TestBinaryTradeOnBothBooks(t *testing.T) {
+	listener := &binaryEventListener{}
+	m := NewBinaryMarket(listener, WithBookID("election"))
+	m.Process(OutcomeNo, *NewOrder("no1", Buy, DecimalBig("1.0"), DecimalBig("0.4")))
+	m.Process(OutcomeYes, *NewOrder("yes1", Buy, DecimalBig("1.0"), DecimalBig("0.6")))
+
+	var trades []*Trade
+	for _, event := range listener.Events {
+		if event.Type == EventTrade {
+			trades = append(trades, event.Trade)
+		}
+	}
+	if len(trades) != 2 {
+		t.Fatalf("complementary trade should be recorded on both books (have: %d)", len(trades))
+	}
+	if trades[0].ID != "election/yes-1" || trades[1].ID != trades[0].ID || trades[1].MakerOrderID != "no1" {
+		t.Fatalf("complementary trade should share one trade ID (have: %s / %s)", trades[0].ID, trades[1].ID)
+	}
+	if m.No.LastSeq() != 2 {
+		t.Fatalf("maker book should sequence the trade (have: %d)", m.No.LastSeq())
+	}
+}
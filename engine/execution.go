@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// ExecType 执行报告类型
+type ExecType string
+
+const (
+	// ExecNew 订单（剩余部分）进入订单簿
+	ExecNew ExecType = "new"
+	// ExecTrade 订单成交
+	ExecTrade ExecType = "trade"
+	// ExecCancelled 订单（剩余部分）被撤销
+	ExecCancelled ExecType = "cancelled"
+	// ExecRejected 订单在撮合前被拒绝
+	ExecRejected ExecType = "rejected"
+	// ExecReplaced 挂单被修改（价格或数量），报告给出修改后的委托数量与剩余数量
+	ExecReplaced ExecType = "replaced"
+)
+
+// OrderStatus 订单状态
+type OrderStatus string
+
+const (
+	StatusNew             OrderStatus = "new"
+	StatusPartiallyFilled OrderStatus = "partially_filled"
+	StatusFilled          OrderStatus = "filled"
+	StatusCancelled       OrderStatus = "cancelled"
+	StatusRejected        OrderStatus = "rejected"
+)
+
+// ExecutionReport 执行报告：事件发生后订单的完整状态
+// 数量与价格均为 int64 格式的定点数 (Scale=1e8)；按金额下单的市价单委托数量与剩余数量为 0
+type ExecutionReport struct {
+	Seq          uint64 // 对应事件的序号（一笔成交的两份报告序号相同）
+	Time         time.Time
+	OrderID      string
+	EngineID     uint64 // 引擎订单 ID，仅在订单挂单或未触发期间唯一
+	Account      string
+	Side         Side
+	ExecType     ExecType
+	Status       OrderStatus
+	Price        int64  // 委托价
+	Quantity     int64  // 委托数量
+	FilledAmount int64  // 累计成交数量
+	LeavesAmount int64  // 剩余未成交数量，撤单、拒单及完全成交后为 0
+	AvgPrice     int64  // 成交均价，尚无成交时为 0
+	LastPrice    int64  // 本次成交价（ExecTrade）
+	LastAmount   int64  // 本次成交数量（ExecTrade）
+	TradeID      string // 成交 ID（ExecTrade）
+	Maker        bool   // 本次成交中订单是否为 Maker（ExecTrade）
+	Reason       string // 撤单或拒单原因
+}
+
+// ExecutionAdapter 把 ExecutionListener 适配为 MatchingListener：原有回调为空操作，执行报告原样转发，
+// 只关心执行报告的调用方可以直接 NewOrderBook(ExecutionAdapter{listener})；已有的 MatchingListener 实现不受影响
+type ExecutionAdapter struct {
+	ExecutionListener
+}
+
+func (a ExecutionAdapter) OnTrade(makerID, takerID string, side Side, price, amount int64) {}
+func (a ExecutionAdapter) OnOrderCancelled(id string)                                      {}
+func (a ExecutionAdapter) OnOrderAccepted(id string)                                       {}
+
+// enter 订单进入撮合时记录委托数量（条件单触发、撤单重下时保留原有记录）
+func (order *Order) enter() {
+	if order.quantity == 0 && order.Funds == nil && order.Amount != nil {
+		order.quantity = order.Amount.Val
+	}
+}
+
+// fill 累计订单的成交数量与成交额
+func (order *Order) fill(price, amount int64) {
+	order.filled += amount
+	order.notional += (&util.StandardBigDecimal{Val: price}).MulFloor(&util.StandardBigDecimal{Val: amount}).Val
+}
+
+// leaves 返回剩余未成交数量
+func (order *Order) leaves() int64 {
+	quantity := order.quantity
+	if quantity == 0 && order.Funds == nil && order.Amount != nil {
+		// 未经撮合流程的订单（如未提交的订单组腿）
+		quantity = order.Amount.Val
+	}
+	if quantity <= order.filled {
+		return 0
+	}
+	return quantity - order.filled
+}
+
+// report 生成订单在本事件后的执行报告
+func (order *Order) report(execType ExecType, reason string) ExecutionReport {
+	r := ExecutionReport{
+		OrderID:      order.ID,
+		EngineID:     order.EID,
+		Account:      order.Account,
+		Side:         order.Type,
+		ExecType:     execType,
+		Quantity:     order.filled + order.leaves(),
+		FilledAmount: order.filled,
+		Reason:       reason,
+	}
+	if order.Funds != nil {
+		r.Quantity = 0
+	}
+	if order.Price != nil {
+		r.Price = order.Price.Val
+	}
+	if order.filled > 0 {
+		r.AvgPrice = (&util.StandardBigDecimal{Val: order.notional}).DivFloor(&util.StandardBigDecimal{Val: order.filled}).Val
+	}
+
+	switch execType {
+	case ExecCancelled:
+		r.Status = StatusCancelled
+	case ExecRejected:
+		r.Status = StatusRejected
+	default:
+		r.LeavesAmount = order.leaves()
+		switch {
+		case order.filled == 0:
+			r.Status = StatusNew
+		case r.LeavesAmount > 0 || order.Funds != nil && order.Funds.Val > 0:
+			r.Status = StatusPartiallyFilled
+		default:
+			r.Status = StatusFilled
+		}
+	}
+	return r
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"testing"
+)
+
+type execListener struct {
+	Reports []ExecutionReport
+}
+
+func (l *execListener) OnExecutionReport(report ExecutionReport) {
+	l.Reports = append(l.Reports, report)
+}
+
+func (l *execListener) last(id string) ExecutionReport {
+	for i := len(l.Reports) - 1; i >= 0; i-- {
+		if l.Reports[i].OrderID == id {
+			return l.Reports[i]
+		}
+	}
+	return ExecutionReport{}
+}
+
+func TestExecutionReportLifecycle(t *testing.T) {
+	listener := &execListener{}
+	ob := NewOrderBook(ExecutionAdapter{listener})
+
+	ob.Process(newBinaryOrder("s1", "maker", Sell, "2.0", "100.0"))
+	ob.Process(newBinaryOrder("b1", "taker", Buy, "3.0", "100.0"))
+	ob.CancelOrder("b1")
+
+	expected := []struct {
+		id       string
+		execType ExecType
+		status   OrderStatus
+		filled   string
+		leaves   string
+		maker    bool
+	}{
+		{"s1", ExecNew, StatusNew, "0", "2.0", false},
+		{"s1", ExecTrade, StatusFilled, "2.0", "0", true},
+		{"b1", ExecTrade, StatusPartiallyFilled, "2.0", "1.0", false},
+		{"b1", ExecNew, StatusPartiallyFilled, "2.0", "1.0", false},
+		{"b1", ExecCancelled, StatusCancelled, "2.0", "0", false},
+	}
+	if len(listener.Reports) != len(expected) {
+		t.Fatalf("expected %d reports (have: %d)", len(expected), len(listener.Reports))
+	}
+	for i, e := range expected {
+		r := listener.Reports[i]
+		if r.OrderID != e.id || r.ExecType != e.execType || r.Status != e.status || r.Maker != e.maker ||
+			r.FilledAmount != DecimalBig(e.filled).Val || r.LeavesAmount != DecimalBig(e.leaves).Val {
+			t.Fatalf("unexpected report %d: %+v", i, r)
+		}
+	}
+
+	trade := listener.Reports[2]
+	if trade.Quantity != DecimalBig("3.0").Val || trade.AvgPrice != DecimalBig("100.0").Val || trade.LastPrice != DecimalBig("100.0").Val ||
+		trade.LastAmount != DecimalBig("2.0").Val || trade.TradeID == "" || trade.TradeID != listener.Reports[1].TradeID || trade.Seq != listener.Reports[1].Seq {
+		t.Fatalf("unexpected trade report %+v", trade)
+	}
+	if r := listener.last("b1"); r.Reason != string(CancelRequested) {
+		t.Fatalf("expected cancel_requested reason (have: %q)", r.Reason)
+	}
+}
+
+func TestExecutionReportAveragePriceAndMarketReason(t *testing.T) {
+	listener := &execListener{}
+	ob := NewOrderBook(ExecutionAdapter{listener})
+
+	ob.Process(newBinaryOrder("s1", "", Sell, "1.0", "100.0"))
+	ob.Process(newBinaryOrder("s2", "", Sell, "1.0", "102.0"))
+	ob.ProcessMarket(newBinaryOrder("m1", "", Buy, "3.0", "0"))
+
+	r := listener.last("m1")
+	if r.ExecType != ExecCancelled || r.Reason != string(CancelNoLiquidity) || r.FilledAmount != DecimalBig("2.0").Val ||
+		r.AvgPrice != DecimalBig("101.0").Val || r.Quantity != DecimalBig("3.0").Val || r.LeavesAmount != 0 {
+		t.Fatalf("unexpected market order report %+v", r)
+	}
+}
+
+func TestExecutionReportReasons(t *testing.T) {
+	listener := &struct {
+		cancelReasonListener
+		execListener
+	}{}
+	ob := NewOrderBook(listener)
+
+	ob.Process(newBinaryOrder("s1", "a", Sell, "1.0", "100.0"))
+	postOnly := newBinaryOrder("p1", "b", Buy, "1.0", "100.0")
+	postOnly.PostOnly = PostOnlyReject
+	ob.Process(postOnly)
+	ioc := newBinaryOrder("i1", "b", Buy, "2.0", "100.0")
+	ioc.TimeInForce = IOC
+	ob.Process(ioc)
+	fok := newBinaryOrder("f1", "b", Sell, "1.0", "100.0")
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	ob.Process(newBinaryOrder("s2", "a", Sell, "1.0", "100.0"))
+	ob.Process(newBinaryOrder("b2", "a", Buy, "1.0", "100.0"))
+
+	if r := listener.last("p1"); r.ExecType != ExecRejected || r.Status != StatusRejected || r.Reason != string(RejectPostOnlyWouldTake) {
+		t.Fatalf("unexpected reject report %+v", r)
+	}
+	for id, reason := range map[string]CancelReason{"i1": CancelImmediateOrCancel, "f1": CancelFillOrKill, "b2": CancelSelfTrade} {
+		if listener.Reasons[id] != reason {
+			t.Fatalf("expected %s cancel reason for %s (have: %s)", reason, id, listener.Reasons[id])
+		}
+		if r := listener.last(id); r.ExecType != ExecCancelled || r.Reason != string(reason) {
+			t.Fatalf("unexpected cancel report %+v", r)
+		}
+	}
+	if r := listener.last("i1"); r.FilledAmount != DecimalBig("1.0").Val || r.Quantity != DecimalBig("2.0").Val {
+		t.Fatalf("IOC remainder should keep its fill (have: %+v)", r)
+	}
+}
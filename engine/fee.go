@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// feeWindowDays 计算手续费等级的滚动成交额窗口（天）
+const feeWindowDays = 30
+
+// FeeSchedule 交易对的手续费表
+type FeeSchedule struct {
+	Currency string    // 手续费币种（通常为报价币种）
+	Tiers    []FeeTier // 按 MinVolume 升序排列，账户适用成交额下限不高于其近 30 天成交额的最高一档
+}
+
+// FeeTier 手续费等级
+type FeeTier struct {
+	MinVolume *util.StandardBigDecimal // 近 30 天成交额下限（含），nil 视为 0
+	MakerRate *util.StandardBigDecimal // Maker 费率，负数表示返佣（如 -0.0001）
+	TakerRate *util.StandardBigDecimal // Taker 费率（如 0.0005）
+}
+
+// TradeFees 单笔成交双方的手续费（定点数，Scale=1e8，负数表示返佣）
+// 成交额为 price × amount 向下取整；手续费为 成交额 × 费率，向正无穷取整（收费向上取整、返佣向下取整）
+type TradeFees struct {
+	MakerFee      int64
+	MakerCurrency string
+	TakerFee      int64
+	TakerCurrency string
+}
+
+// feeBook 手续费表与各账户的滚动成交额
+type feeBook struct {
+	schedule FeeSchedule
+	volumes  map[string]*rollingVolume
+}
+
+// rollingVolume 按自然日（UTC）分桶的滚动成交额
+type rollingVolume struct {
+	days    [feeWindowDays]int64 // 桶对应的日期序号
+	volumes [feeWindowDays]int64
+}
+
+// SetFeeSchedule 设置手续费表，之后的成交按账户近 30 天成交额（本交易对，Maker 与 Taker 合计）所在等级计费
+// 并通过 FeeListener 上报；schedule 无等级时关闭计费
+func (ob *OrderBook) SetFeeSchedule(schedule FeeSchedule) error {
+	for i, tier := range schedule.Tiers {
+		if tier.MakerRate == nil || tier.TakerRate == nil {
+			return errors.New("fee rate is required")
+		}
+		if abs(tier.MakerRate.Val) >= util.SCALE || abs(tier.TakerRate.Val) >= util.SCALE {
+			return errors.New("fee rate should be between -1 and 1")
+		}
+		if i > 0 && tier.minVolume() <= schedule.Tiers[i-1].minVolume() {
+			return errors.New("fee tiers should be sorted by min volume")
+		}
+	}
+
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	if len(schedule.Tiers) == 0 {
+		ob.fees = nil
+		return nil
+	}
+	volumes := map[string]*rollingVolume{}
+	if ob.fees != nil {
+		// 更换费率不影响已统计的成交额
+		volumes = ob.fees.volumes
+	}
+	ob.fees = &feeBook{schedule: schedule, volumes: volumes}
+	return nil
+}
+
+// AccountVolume 返回账户在本交易对近 30 天的成交额（未设置手续费表时为 0）
+func (ob *OrderBook) AccountVolume(account string) *util.StandardBigDecimal {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	if ob.fees == nil {
+		return &util.StandardBigDecimal{}
+	}
+	return &util.StandardBigDecimal{Val: ob.fees.volume(account, ob.clock())}
+}
+
+// chargeFees 按双方订单簿的手续费表计算一笔成交的手续费并上报 FeeListener，双方均未设置手续费表时返回 nil
+// 互补撮合（mint / merge）时双方位于不同订单簿、按各自价格计费
+func chargeFees(makerBook, takerBook *OrderBook, maker, taker *Order, makerPrice, takerPrice, amount int64) *TradeFees {
+	if makerBook.fees == nil && takerBook.fees == nil {
+		return nil
+	}
+	now := takerBook.clock()
+	var fees TradeFees
+	if makerBook.fees != nil {
+		fees.MakerFee = makerBook.fees.charge(maker.Account, true, makerPrice, amount, now)
+		fees.MakerCurrency = makerBook.fees.schedule.Currency
+	}
+	if takerBook.fees != nil {
+		fees.TakerFee = takerBook.fees.charge(taker.Account, false, takerPrice, amount, now)
+		fees.TakerCurrency = takerBook.fees.schedule.Currency
+	}
+	if takerBook.feeListener != nil {
+		takerBook.feeListener.OnTradeFee(maker.ID, taker.ID, fees)
+	}
+	return &fees
+}
+
+// charge 按账户当前等级计算手续费，并把本笔成交额计入账户的滚动成交额
+func (fb *feeBook) charge(account string, maker bool, price, amount int64, now time.Time) int64 {
+	notional := (&util.StandardBigDecimal{Val: price}).MulFloor(&util.StandardBigDecimal{Val: amount})
+	tier := fb.tier(fb.volume(account, now))
+	rate := tier.TakerRate
+	if maker {
+		rate = tier.MakerRate
+	}
+	fee := notional.MulCeil(rate).Val
+	if rate.Val < 0 {
+		// 返佣：按绝对值向下取整
+		fee = -notional.MulFloor(rate.Neg()).Val
+	}
+
+	if account != "" {
+		fb.add(account, notional.Val, now)
+	}
+	return fee
+}
+
+// tier 返回成交额对应的手续费等级
+func (fb *feeBook) tier(volume int64) FeeTier {
+	tier := fb.schedule.Tiers[0]
+	for _, t := range fb.schedule.Tiers[1:] {
+		if volume < t.minVolume() {
+			break
+		}
+		tier = t
+	}
+	return tier
+}
+
+// volume 返回账户截至 now 的近 30 天成交额（无账户 ID 的订单不统计）
+func (fb *feeBook) volume(account string, now time.Time) int64 {
+	rv, ok := fb.volumes[account]
+	if !ok || account == "" {
+		return 0
+	}
+	today := dayIndex(now)
+	var total int64
+	for i, day := range rv.days {
+		if day > today-feeWindowDays && day <= today {
+			total += rv.volumes[i]
+		}
+	}
+	return total
+}
+
+// add 把成交额计入账户当天的分桶（分桶已过期时先清零）
+func (fb *feeBook) add(account string, notional int64, now time.Time) {
+	rv, ok := fb.volumes[account]
+	if !ok {
+		rv = &rollingVolume{}
+		fb.volumes[account] = rv
+	}
+	today := dayIndex(now)
+	slot := today % feeWindowDays
+	if rv.days[slot] != today {
+		rv.days[slot] = today
+		rv.volumes[slot] = 0
+	}
+	rv.volumes[slot] += notional
+}
+
+// minVolume 返回等级的成交额下限
+func (tier FeeTier) minVolume() int64 {
+	if tier.MinVolume == nil {
+		return 0
+	}
+	return tier.MinVolume.Val
+}
+
+// dayIndex 返回 UTC 自然日序号
+func dayIndex(t time.Time) int64 {
+	return t.Unix() / 86400
+}
+
+// abs 返回绝对值
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
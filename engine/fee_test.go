@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type feeListener struct {
+	MockListener
+	Fees []TradeFees
+}
+
+func (l *feeListener) OnTradeFee(makerID, takerID string, fees TradeFees) {
+	l.Fees = append(l.Fees, fees)
+}
+
+func testSchedule() FeeSchedule {
+	return FeeSchedule{
+		Currency: "USDT",
+		Tiers: []FeeTier{
+			{MakerRate: DecimalBig("0.001"), TakerRate: DecimalBig("0.002")},
+			{MinVolume: DecimalBig("1000.0"), MakerRate: DecimalBig("-0.0001"), TakerRate: DecimalBig("0.001")},
+		},
+	}
+}
+
+func TestFeeRoundingAndRebate(t *testing.T) {
+	listener := &feeListener{}
+	ob := NewOrderBook(listener)
+	if err := ob.SetFeeSchedule(testSchedule()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 成交额 3 × 33.33333333 = 99.99999999；Maker 0.1% 向上取整，Taker 0.2% 向上取整
+	ob.Process(newBinaryOrder("s1", "maker", Sell, "3.0", "33.33333333"))
+	ob.Process(newBinaryOrder("b1", "taker", Buy, "3.0", "33.33333333"))
+	if len(listener.Fees) != 1 {
+		t.Fatal("expected one fee event")
+	}
+	fees := listener.Fees[0]
+	if fees.MakerFee != DecimalBig("0.1").Val || fees.TakerFee != DecimalBig("0.2").Val || fees.MakerCurrency != "USDT" || fees.TakerCurrency != "USDT" {
+		t.Fatalf("unexpected fees %+v", fees)
+	}
+	if ob.AccountVolume("maker").Cmp(DecimalBig("99.99999999")) != 0 {
+		t.Fatal("traded notional should count towards volume")
+	}
+
+	// 累计成交额达到 1000 后 Maker 进入返佣等级
+	ob.Process(newBinaryOrder("s2", "maker", Sell, "10.0", "100.0"))
+	ob.Process(newBinaryOrder("b2", "taker", Buy, "10.0", "100.0"))
+	ob.Process(newBinaryOrder("s3", "maker", Sell, "0.33333333", "3.0"))
+	ob.Process(newBinaryOrder("b3", "other", Buy, "0.33333333", "3.0"))
+	fees = listener.Fees[2]
+	if fees.MakerFee != -DecimalBig("0.00009999").Val || fees.TakerFee != DecimalBig("0.002").Val {
+		t.Fatalf("rebate should round towards zero (have: %+v)", fees)
+	}
+}
+
+func TestFeeRollingWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	listener := &feeListener{}
+	ob := NewOrderBook(listener, WithClock(func() time.Time { return now }))
+	ob.SetFeeSchedule(testSchedule())
+
+	ob.Process(newBinaryOrder("s1", "maker", Sell, "20.0", "100.0"))
+	ob.Process(newBinaryOrder("b1", "taker", Buy, "20.0", "100.0"))
+	if ob.AccountVolume("taker").Cmp(DecimalBig("2000.0")) != 0 {
+		t.Fatal("unexpected volume")
+	}
+
+	now = now.AddDate(0, 0, 29)
+	if ob.AccountVolume("taker").Cmp(DecimalBig("2000.0")) != 0 {
+		t.Fatal("volume should stay within the 30-day window")
+	}
+	now = now.AddDate(0, 0, 1)
+	if ob.AccountVolume("taker").Val != 0 {
+		t.Fatal("volume older than 30 days should roll off")
+	}
+}
+
+func TestFeeScheduleValidation(t *testing.T) {
+	ob := NewOrderBook(&MockListener{})
+	unsorted := testSchedule()
+	unsorted.Tiers[0], unsorted.Tiers[1] = unsorted.Tiers[1], unsorted.Tiers[0]
+	if ob.SetFeeSchedule(unsorted) == nil {
+		t.Fatal("unsorted tiers should fail")
+	}
+	if ob.SetFeeSchedule(FeeSchedule{Tiers: []FeeTier{{MakerRate: DecimalBig("1.0"), TakerRate: DecimalBig("0.1")}}}) == nil {
+		t.Fatal("rate of 100% should fail")
+	}
+	if ob.SetFeeSchedule(FeeSchedule{}) != nil || ob.fees != nil {
+		t.Fatal("empty schedule should disable fees")
+	}
+}
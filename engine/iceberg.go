@@ -0,0 +1,74 @@
+package engine
+
+import "github.com/goovo/matching-engine/util"
+
+// isIceberg 判断挂单时是否按冰山单处理（显示数量有效且小于总数量）
+func (order *Order) isIceberg() bool {
+	return order.DisplayAmount != nil && order.DisplayAmount.Val > 0 && order.DisplayAmount.Cmp(order.Amount) == -1
+}
+
+// splitIceberg 挂单前把冰山单拆成显示部分与隐藏储备
+// 仅显示部分计入 OrderNode.Volume 与深度
+func (order *Order) splitIceberg() {
+	if !order.isIceberg() {
+		order.hidden = nil
+		return
+	}
+	order.hidden = order.Amount.Sub(order.DisplayAmount)
+	order.Amount = order.DisplayAmount.Clone()
+}
+
+// refill 用隐藏储备补充显示数量，返回是否补充成功
+func (order *Order) refill() bool {
+	if order.hidden == nil || order.hidden.Val <= 0 {
+		return false
+	}
+	slice := order.DisplayAmount.Val
+	if order.hidden.Val < slice {
+		slice = order.hidden.Val
+	}
+	order.Amount.Val = slice
+	order.hidden.Val -= slice
+	return true
+}
+
+// remainingVal 返回订单剩余总量（显示 + 隐藏）的定点数，不分配内存
+func (order *Order) remainingVal() int64 {
+	if order.hidden == nil {
+		return order.Amount.Val
+	}
+	return order.Amount.Val + order.hidden.Val
+}
+
+// levelRemaining 返回价格档位内全部订单的剩余总量（含冰山单的隐藏储备，OrderNode.Volume 只含显示部分）
+func levelRemaining(arena *OrderArena, node *OrderNode) int64 {
+	var total int64
+	for idx := node.Head; idx != NullIndex; {
+		order := arena.Get(idx)
+		total += order.remainingVal()
+		idx = order.Next
+	}
+	return total
+}
+
+// remaining 返回订单剩余总量（显示 + 隐藏）
+func (order *Order) remaining() *util.StandardBigDecimal {
+	if order.hidden == nil {
+		return order.Amount.Clone()
+	}
+	return order.Amount.Add(order.hidden)
+}
+
+// removeFilledMaker 处理显示部分完全成交的 Maker：
+// 冰山单刷新显示数量后排到该价格档位队尾，否则移出订单簿
+func (ob *OrderBook) removeFilledMaker(node *OrderNode, idx IndexType) {
+	ele := ob.Arena.Get(idx)
+	if ele.hidden != nil && ele.hidden.Val > 0 {
+		node.unlinkOrder(ob.Arena, idx)
+		ele.refill()
+		node.addOrder(ob.Arena, idx)
+		return
+	}
+	ob.forget(ele)
+	node.removeOrder(ob.Arena, idx)
+}
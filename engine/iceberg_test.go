@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"testing"
+)
+
+func newIceberg(id string, side Side, amount, display, price string) Order {
+	order := NewOrder(id, side, DecimalBig(amount), DecimalBig(price))
+	order.DisplayAmount = DecimalBig(display)
+	return *order
+}
+
+func TestIcebergDisplayDepth(t *testing.T) {
+	ob := NewOrderBook(nil)
+	ob.Process(newIceberg("ice", Sell, "10.0", "2.0", "100.0"))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	book := ob.GetOrders(0)
+	if len(book.Sells) != 1 || book.Sells[0][1] != "3" {
+		t.Fatalf("only display quantity should count toward depth (have: %v)", book.Sells)
+	}
+}
+
+func TestIcebergRefillGoesToBackOfQueue(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newIceberg("ice", Sell, "10.0", "2.0", "100.0"))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("3.0"), DecimalBig("100.0")))
+
+	want := []MockTrade{
+		{MakerID: "ice", TakerID: "b1", Price: DecimalBig("100.0").Val, Amount: DecimalBig("2.0").Val},
+		{MakerID: "s2", TakerID: "b1", Price: DecimalBig("100.0").Val, Amount: DecimalBig("1.0").Val},
+	}
+	if len(listener.Trades) != len(want) {
+		t.Fatalf("trade count (have: %d, want: %d)", len(listener.Trades), len(want))
+	}
+	for i := range want {
+		if listener.Trades[i] != want[i] {
+			t.Fatalf("trade %d (have: %+v, want: %+v)", i, listener.Trades[i], want[i])
+		}
+	}
+
+	idx := indexOf(ob, "ice")
+	node := ob.Levels.Get(ob.Arena.Get(idx).Node)
+	if node.Count != 1 || node.Volume.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatalf("refilled slice should be visible (count: %d, volume: %s)", node.Count, node.Volume.String())
+	}
+
+	cancelled := ob.CancelOrder("ice")
+	if cancelled == nil || cancelled.Amount.Cmp(DecimalBig("8.0")) != 0 {
+		t.Fatalf("cancel should return visible plus hidden quantity (have: %v)", cancelled)
+	}
+}
+
+func TestIcebergSweep(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newIceberg("ice", Buy, "10.0", "2.0", "100.0"))
+
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("9.0"), DecimalBig("100.0")))
+
+	if len(listener.Trades) != 5 {
+		t.Fatalf("taker should fill slice by slice (have: %d trades)", len(listener.Trades))
+	}
+	idx, ok := ob.lookup("ice")
+	if !ok {
+		t.Fatal("iceberg with remaining quantity should stay in book")
+	}
+	if order := ob.Arena.Get(idx); order.remaining().Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("remaining quantity (have: %s, want: 1)", order.remaining())
+	}
+	if _, ok := ob.lookup("s1"); ok {
+		t.Fatal("taker should be fully filled")
+	}
+}
+
+func TestIcebergTakerRestsAsIceberg(t *testing.T) {
+	ob := NewOrderBook(nil)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(newIceberg("ice", Buy, "10.0", "2.0", "100.0"))
+
+	book := ob.GetOrders(0)
+	if len(book.Buys) != 1 || book.Buys[0][1] != "2" {
+		t.Fatalf("remainder should rest with display quantity (have: %v)", book.Buys)
+	}
+	if order := ob.Arena.Get(indexOf(ob, "ice")); order.remaining().Cmp(DecimalBig("9.0")) != 0 {
+		t.Fatalf("remaining quantity (have: %s, want: 9)", order.remaining())
+	}
+}
+
+func TestIcebergFOKCountsHiddenReserve(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newIceberg("ice", Sell, "10.0", "2.0", "100.0"))
+
+	fok := *NewOrder("b1", Buy, DecimalBig("5.0"), DecimalBig("100.0"))
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	if len(listener.Trades) != 3 || len(listener.Cancelled) != 0 {
+		t.Fatalf("FOK should fill against the hidden reserve (have: %+v, cancelled: %v)", listener.Trades, listener.Cancelled)
+	}
+
+	fok = *NewOrder("b2", Buy, DecimalBig("6.0"), DecimalBig("100.0"))
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	if len(listener.Trades) != 3 || len(listener.Cancelled) != 1 {
+		t.Fatalf("FOK beyond the remaining reserve should be killed (have: %+v)", listener.Trades)
+	}
+}
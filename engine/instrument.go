@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// InstrumentSpec 交易规则：价格与数量的最小变动单位、订单数量上下限、最小成交额及价格范围
+// 除 TickSize 与 LotSize 外，字段为 nil 时不限制
+type InstrumentSpec struct {
+	TickSize    *util.StandardBigDecimal // 最小价格变动单位（价格与触发价须为其整数倍）
+	LotSize     *util.StandardBigDecimal // 最小数量变动单位（数量与冰山单显示数量须为其整数倍）
+	MinQty      *util.StandardBigDecimal // 单笔最小数量
+	MaxQty      *util.StandardBigDecimal // 单笔最大数量
+	MinNotional *util.StandardBigDecimal // 最小成交额（限价单为 价格 × 数量，按金额下单的市价单为 Funds）
+	MinPrice    *util.StandardBigDecimal // 最低价格（如二元期权结果代币 0.001）
+	MaxPrice    *util.StandardBigDecimal // 最高价格（如二元期权结果代币 0.999）
+}
+
+// defaultSpec 默认交易规则：最小变动单位为 1e-8，不限制数量、成交额与价格
+func defaultSpec() InstrumentSpec {
+	return InstrumentSpec{
+		TickSize: &util.StandardBigDecimal{Val: 1},
+		LotSize:  &util.StandardBigDecimal{Val: 1},
+	}
+}
+
+// InstrumentSpec 返回订单簿当前的交易规则
+func (ob *OrderBook) InstrumentSpec() InstrumentSpec {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	return ob.spec
+}
+
+// SetInstrumentSpec 设置交易规则，之后提交的订单按新规则检查（已挂出的订单不受影响）
+// TickSize、LotSize 为 nil 时使用默认值 1e-8
+func (ob *OrderBook) SetInstrumentSpec(spec InstrumentSpec) error {
+	if err := spec.normalize(); err != nil {
+		return err
+	}
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.spec = spec
+	return nil
+}
+
+// SetTickSize 设置最小价格变动单位
+func (ob *OrderBook) SetTickSize(tick *util.StandardBigDecimal) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.spec.TickSize = tick.Clone()
+}
+
+// SetLotSize 设置最小数量变动单位（按报价金额下单时数量向下取整到该单位）
+func (ob *OrderBook) SetLotSize(lot *util.StandardBigDecimal) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.spec.LotSize = lot.Clone()
+}
+
+// normalize 填充默认值并检查交易规则自身是否合法
+func (spec *InstrumentSpec) normalize() error {
+	defaults := defaultSpec()
+	if spec.TickSize == nil {
+		spec.TickSize = defaults.TickSize
+	}
+	if spec.LotSize == nil {
+		spec.LotSize = defaults.LotSize
+	}
+	if spec.TickSize.Val <= 0 || spec.LotSize.Val <= 0 {
+		return errors.New("tick size and lot size should be greater than zero")
+	}
+	if spec.MinQty != nil && spec.MaxQty != nil && spec.MinQty.Cmp(spec.MaxQty) == 1 {
+		return errors.New("min quantity should not exceed max quantity")
+	}
+	if spec.MinPrice != nil && spec.MaxPrice != nil && spec.MinPrice.Cmp(spec.MaxPrice) == 1 {
+		return errors.New("min price should not exceed max price")
+	}
+	return nil
+}
+
+// Validate 按交易规则检查订单，违反时返回以拒单原因为内容的错误
+// market 为 true 时按市价单检查（不检查限价与成交额，按金额下单时检查 Funds）
+func (spec InstrumentSpec) Validate(order *Order, market bool) error {
+	if reason := spec.check(order, market); reason != "" {
+		return errors.New(string(reason))
+	}
+	return nil
+}
+
+// check 按交易规则检查订单，返回空字符串表示通过
+func (spec InstrumentSpec) check(order *Order, market bool) RejectReason {
+	if !market && order.Price != nil {
+		if reason := spec.checkPrice(order.Price); reason != "" {
+			return reason
+		}
+	}
+	if order.StopPrice != nil && order.StopPrice.Val%spec.TickSize.Val != 0 {
+		return RejectTickSize
+	}
+	if order.DisplayAmount != nil && order.DisplayAmount.Val%spec.LotSize.Val != 0 {
+		return RejectLotSize
+	}
+
+	if market && order.Funds != nil {
+		// 按金额下单：数量由撮合时逐档计算
+		if spec.MinNotional != nil && order.Funds.Cmp(spec.MinNotional) == -1 {
+			return RejectMinNotional
+		}
+		return ""
+	}
+	if order.Amount == nil {
+		return ""
+	}
+	if reason := spec.checkAmount(order.Amount); reason != "" {
+		return reason
+	}
+	if !market && spec.MinNotional != nil && order.Price.MulFloor(order.Amount).Cmp(spec.MinNotional) == -1 {
+		return RejectMinNotional
+	}
+	return ""
+}
+
+// checkPrice 检查价格范围与最小价格变动单位
+func (spec InstrumentSpec) checkPrice(price *util.StandardBigDecimal) RejectReason {
+	if spec.MinPrice != nil && price.Cmp(spec.MinPrice) == -1 || spec.MaxPrice != nil && price.Cmp(spec.MaxPrice) == 1 {
+		return RejectPriceRange
+	}
+	if price.Val%spec.TickSize.Val != 0 {
+		return RejectTickSize
+	}
+	return ""
+}
+
+// checkAmount 检查最小数量变动单位与数量上下限
+func (spec InstrumentSpec) checkAmount(amount *util.StandardBigDecimal) RejectReason {
+	if amount.Val%spec.LotSize.Val != 0 {
+		return RejectLotSize
+	}
+	if spec.MinQty != nil && amount.Cmp(spec.MinQty) == -1 {
+		return RejectMinQty
+	}
+	if spec.MaxQty != nil && amount.Cmp(spec.MaxQty) == 1 {
+		return RejectMaxQty
+	}
+	return ""
+}
+
+// rejectInvalid 订单违反交易规则时拒单，返回 true 表示已拒绝
+func (ob *OrderBook) rejectInvalid(order *Order, market bool) bool {
+	if reason := ob.spec.check(order, market); reason != "" {
+		ob.rejectOrder(order, reason)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"testing"
+)
+
+func binarySpec() InstrumentSpec {
+	return InstrumentSpec{
+		TickSize:    DecimalBig("0.001"),
+		LotSize:     DecimalBig("1.0"),
+		MinQty:      DecimalBig("5.0"),
+		MaxQty:      DecimalBig("1000.0"),
+		MinNotional: DecimalBig("1.0"),
+		MinPrice:    DecimalBig("0.001"),
+		MaxPrice:    DecimalBig("0.999"),
+	}
+}
+
+func TestInstrumentSpecRejects(t *testing.T) {
+	var tests = []struct {
+		name   string
+		amount string
+		price  string
+		reason RejectReason
+	}{
+		{"valid", "10.0", "0.5", ""},
+		{"tick", "10.0", "0.5005", RejectTickSize},
+		{"lot", "10.5", "0.5", RejectLotSize},
+		{"min qty", "4.0", "0.5", RejectMinQty},
+		{"max qty", "1001.0", "0.5", RejectMaxQty},
+		{"min notional", "5.0", "0.1", RejectMinNotional},
+		{"price min", "10.0", "0.0005", RejectPriceRange},
+		{"price max", "10.0", "1.0", RejectPriceRange},
+	}
+
+	for _, tt := range tests {
+		listener := &rejectListener{}
+		ob := NewOrderBook(listener)
+		if err := ob.SetInstrumentSpec(binarySpec()); err != nil {
+			t.Fatal(err)
+		}
+		ob.Process(*NewOrder("o", Buy, DecimalBig(tt.amount), DecimalBig(tt.price)))
+		if listener.Rejected["o"] != tt.reason {
+			t.Fatalf("%s: expected reject %q (have: %q)", tt.name, tt.reason, listener.Rejected["o"])
+		}
+		if _, resting := ob.lookup("o"); resting == (tt.reason != "") {
+			t.Fatalf("%s: rejected orders should not rest", tt.name)
+		}
+	}
+}
+
+func TestInstrumentSpecMarketAndStop(t *testing.T) {
+	listener := &rejectListener{}
+	ob := NewOrderBook(listener)
+	ob.SetInstrumentSpec(binarySpec())
+
+	ob.ProcessMarket(*NewOrder("m1", Buy, DecimalBig("2.0"), DecimalBig("0.0")))
+	if listener.Rejected["m1"] != RejectMinQty {
+		t.Fatal("market order amount should be checked")
+	}
+	quote := NewOrder("m2", Buy, nil, nil)
+	quote.Funds = DecimalBig("0.5")
+	ob.ProcessMarket(*quote)
+	if listener.Rejected["m2"] != RejectMinNotional {
+		t.Fatal("quote market order funds should be checked against min notional")
+	}
+	ob.Process(newStop("s1", Buy, "10.0", "0.6", "0.5505"))
+	if listener.Rejected["s1"] != RejectTickSize {
+		t.Fatal("stop price should be checked against tick size")
+	}
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("10.0"), DecimalBig("0.5")))
+	if _, err := ob.AmendOrder("b1", DecimalBig("0.5005"), nil); err == nil || err.Error() != string(RejectTickSize) {
+		t.Fatalf("amend should be checked against tick size (have: %v)", err)
+	}
+	if _, err := ob.AmendOrder("b1", nil, DecimalBig("3.0")); err == nil || err.Error() != string(RejectMinQty) {
+		t.Fatalf("amend should be checked against min quantity (have: %v)", err)
+	}
+}
+
+func TestInstrumentSpecInvalid(t *testing.T) {
+	ob := NewOrderBook(&MockListener{})
+	if ob.SetInstrumentSpec(InstrumentSpec{TickSize: DecimalBig("0.0")}) == nil {
+		t.Fatal("zero tick size should fail")
+	}
+	if ob.SetInstrumentSpec(InstrumentSpec{MinPrice: DecimalBig("0.9"), MaxPrice: DecimalBig("0.1")}) == nil {
+		t.Fatal("inverted price range should fail")
+	}
+	if err := ob.SetInstrumentSpec(InstrumentSpec{}); err != nil || ob.InstrumentSpec().TickSize.Val != 1 {
+		t.Fatal("empty spec should use defaults")
+	}
+}
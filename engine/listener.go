@@ -15,6 +15,150 @@ type MatchingListener interface {
 	OnOrderAccepted(orderID string)
 }
 
+// RejectListener 可选的拒单回调接口
+// MatchingListener 的实现同时实现该接口即可收到拒单原因；
+// 未实现时拒单通过 OnOrderCancelled 通知
+type RejectListener interface {
+	// OnOrderRejected 当订单在撮合前被拒绝时触发
+	OnOrderRejected(orderID string, reason RejectReason)
+}
+
+// StopListener 可选的条件单回调接口
+type StopListener interface {
+	// OnStopAccepted 当条件单进入条件单簿时触发
+	OnStopAccepted(orderID string)
+
+	// OnStopTriggered 当条件单被最新成交价 lastPrice 触发、即将注入撮合时触发
+	OnStopTriggered(orderID string, lastPrice int64)
+}
+
+// SelfTradeListener 可选的自成交防护回调接口
+type SelfTradeListener interface {
+	// OnSelfTradePrevented 当 Taker 与同一账户的 Maker 相遇、按 mode 阻止成交时触发
+	OnSelfTradePrevented(makerOrderID, takerOrderID string, mode STPMode)
+}
+
+// DustListener 可选的剩余金额回调接口
+type DustListener interface {
+	// OnOrderDust 按报价金额下单的市价单结束时仍有未花完的金额 funds（不足一个最小数量单位或对手盘不足），
+	// 随后触发 OnOrderCancelled
+	OnOrderDust(orderID string, funds int64)
+}
+
+// ConversionListener 可选的 neg-risk 转换回调接口（MarketGroup 的 listener 实现时生效）
+type ConversionListener interface {
+	// OnConversion 当账户 account 的 amount 份第 outcome 个结果的 NO 转换为其余各结果的 YES 时触发；
+	// 由跨结果路由成交产生时 orderID 为 Taker 订单 ID（手动 Convert 时为空），
+	// amount 为负表示 Taker 买入的其余各结果 YES 组合按 NO 记入持仓
+	OnConversion(account, orderID string, outcome int, amount int64)
+}
+
+// FeeListener 可选的手续费回调接口（订单簿设置了手续费表时生效）
+type FeeListener interface {
+	// OnTradeFee 在每笔成交的 OnTrade（或 OnBinaryTrade）之后触发，给出双方的手续费
+	OnTradeFee(makerOrderID, takerOrderID string, fees TradeFees)
+}
+
+// EventListener 可选的事件记录回调接口
+type EventListener interface {
+	// OnEvent 在对应的 MatchingListener（及可选接口）回调之后触发；同一订单簿的事件序号连续递增，
+	// 时间取自订单簿时钟（见 WithClock）
+	OnEvent(event Event)
+}
+
+// ExecutionListener 可选的执行报告回调接口
+type ExecutionListener interface {
+	// OnExecutionReport 在对应的 MatchingListener（及可选接口）回调之后触发：
+	// 订单进入订单簿、每笔成交（Maker 与 Taker 各一份）、撤单与拒单各产生一份执行报告，条件单触发前不产生
+	OnExecutionReport(report ExecutionReport)
+}
+
+// CancelListener 可选的撤单原因回调接口
+type CancelListener interface {
+	// OnOrderCancelReason 在 OnOrderCancelled 之前触发，说明订单（剩余部分）被撤销的原因
+	OnOrderCancelReason(orderID string, reason CancelReason)
+}
+
+// CancelReason 撤单原因
+type CancelReason string
+
+const (
+	// CancelPriceProtection 市价单触及保护价（或最大滑点）后剩余部分撤销
+	CancelPriceProtection CancelReason = "price_protection"
+	// CancelMassCancel 被 MassCancel 批量撤销
+	CancelMassCancel CancelReason = "mass_cancel"
+	// CancelVolatilityHalt 成交价超出价格带触发熔断，市价单剩余部分撤销
+	CancelVolatilityHalt CancelReason = "volatility_halt"
+	// CancelMarketResolved 预测市场结算时撤销全部挂单与条件单
+	CancelMarketResolved CancelReason = "market_resolved"
+	// CancelBidsExceedOne 多结果市场中剩余部分挂单后各结果 YES 最优买价之和将超过 1，剩余部分撤销
+	CancelBidsExceedOne CancelReason = "bids_exceed_one"
+	// CancelRequested 通过 CancelOrder 主动撤单（只出现在事件记录与执行报告中）
+	CancelRequested CancelReason = "cancel_requested"
+	// CancelImmediateOrCancel IOC 订单未成交的剩余部分撤销
+	CancelImmediateOrCancel CancelReason = "immediate_or_cancel"
+	// CancelFillOrKill FOK 订单无法全部成交，整体撤销
+	CancelFillOrKill CancelReason = "fill_or_kill"
+	// CancelNoLiquidity 市价单扫完对手盘后剩余部分撤销
+	CancelNoLiquidity CancelReason = "no_liquidity"
+	// CancelInsufficientFunds 按金额下单的市价单剩余金额不足一个最小数量单位
+	CancelInsufficientFunds CancelReason = "insufficient_funds"
+	// CancelSelfTrade 按自成交防护模式撤销 Taker 或 Maker
+	CancelSelfTrade CancelReason = "self_trade_prevention"
+	// CancelOrderGroup 所属 OCO / 括号单订单组已触发，撤销其余腿
+	CancelOrderGroup CancelReason = "order_group"
+)
+
+// StateListener 可选的交易状态回调接口
+type StateListener interface {
+	// OnTradingStateChanged 当订单簿交易状态变化时触发
+	OnTradingStateChanged(state TradingState, reason StateChangeReason)
+}
+
+// StateChangeReason 交易状态变化原因
+type StateChangeReason string
+
+const (
+	// StateChangeManual 由 SetTradingState / StartAuction / Uncross 主动切换
+	StateChangeManual StateChangeReason = "manual"
+	// StateChangeVolatility 成交价超出价格带自动熔断
+	StateChangeVolatility StateChangeReason = "volatility"
+	// StateChangeResolution 预测市场结算
+	StateChangeResolution StateChangeReason = "resolution"
+)
+
+// RejectReason 拒单原因
+type RejectReason string
+
+const (
+	// RejectPostOnlyWouldTake post-only 订单会成为 Taker
+	RejectPostOnlyWouldTake RejectReason = "post_only_would_take"
+	// RejectAuction 集合竞价阶段不接受市价单与 IOC/FOK 订单
+	RejectAuction RejectReason = "auction_in_progress"
+	// RejectHalted 订单簿处于熔断（暂停交易）状态
+	RejectHalted RejectReason = "trading_halted"
+	// RejectClosed 订单簿处于休市状态
+	RejectClosed RejectReason = "market_closed"
+	// RejectResolved 预测市场已结算
+	RejectResolved RejectReason = "market_resolved"
+	// RejectBidsExceedOne 多结果市场中挂单后各结果 YES 最优买价之和将超过 1
+	RejectBidsExceedOne RejectReason = "bids_exceed_one"
+	// RejectTickSize 价格或触发价不是最小价格变动单位的整数倍
+	RejectTickSize RejectReason = "invalid_tick_size"
+	// RejectLotSize 数量不是最小数量变动单位的整数倍
+	RejectLotSize RejectReason = "invalid_lot_size"
+	// RejectMinQty 数量低于单笔最小数量
+	RejectMinQty RejectReason = "below_min_quantity"
+	// RejectMaxQty 数量超过单笔最大数量
+	RejectMaxQty RejectReason = "above_max_quantity"
+	// RejectMinNotional 成交额低于最小成交额
+	RejectMinNotional RejectReason = "below_min_notional"
+	// RejectPriceRange 价格超出允许范围
+	RejectPriceRange RejectReason = "price_out_of_range"
+	// RejectDuplicateOrderID 订单 ID 与挂单或未触发的条件单重复
+	RejectDuplicateOrderID RejectReason = "duplicate_order_id"
+)
+
 // NoOpListener 空实现，用于默认情况
 type NoOpListener struct{}
 
@@ -0,0 +1,377 @@
+package engine
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// MarketGroup 多结果互斥事件（neg-risk）的市场组：每个结果对应一个 YES/NO 二元市场，
+// 恰有一个结果为 YES。第 i 个结果的 1 份 NO 等价于（并可转换为）其余每个结果各 1 份 YES，
+// 因此 NO 订单除本市场的直接与互补撮合外，还会按跨结果路由与其余结果的 YES 订单簿成交：
+// 买入 NO_i 即从其余结果的 YES 卖单各买入 1 份（价格之和不高于限价），卖出 NO_i 即转换为其余结果的 YES 后
+// 分别卖给各 YES 买单（价格之和不低于限价）
+type MarketGroup struct {
+	Markets            []*BinaryMarket
+	conversionListener ConversionListener
+}
+
+// routeLeg 跨结果路由中单个 YES 订单簿的最优挂单
+type routeLeg struct {
+	book *OrderBook
+	tree PriceLevels
+	node *OrderNode
+	idx  IndexType
+}
+
+// NewMarketGroup 返回包含 outcomes 个结果的市场组，各订单簿共用 listener 与构造选项
+func NewMarketGroup(outcomes int, listener BinaryListener, opts ...BookOption) (*MarketGroup, error) {
+	if outcomes < 2 {
+		return nil, errors.New("market group needs at least two outcomes")
+	}
+	g := &MarketGroup{Markets: make([]*BinaryMarket, outcomes)}
+	g.conversionListener, _ = listener.(ConversionListener)
+	for i := range g.Markets {
+		// 各市场的成交 ID 以结果序号区分
+		index := strconv.Itoa(i)
+		marketOpts := append(opts[:len(opts):len(opts)], func(ob *OrderBook) {
+			ob.bookID = subBookID(ob.bookID, index)
+		})
+		g.Markets[i] = NewBinaryMarket(listener, marketOpts...)
+		g.Markets[i].group = g
+		g.Markets[i].index = i
+	}
+	return g, nil
+}
+
+// Process 提交第 index 个结果的 outcome 限价单
+func (g *MarketGroup) Process(index int, outcome Outcome, order Order) {
+	g.lock()
+	defer g.unlock()
+
+	m := g.Markets[index]
+	if order.StopPrice != nil {
+		m.Book(outcome).addStopOrder(order, false)
+	} else {
+		m.process(outcome, order)
+	}
+	for _, market := range g.Markets {
+		market.Yes.triggerStops()
+		market.No.triggerStops()
+	}
+}
+
+// Convert 把账户 account 持有的 amount 份第 index 个结果的 NO 转换为其余各结果的 YES（只调整持仓记录）
+func (g *MarketGroup) Convert(account string, index int, amount *util.StandardBigDecimal) error {
+	if index < 0 || index >= len(g.Markets) {
+		return errors.New("invalid outcome")
+	}
+	if amount == nil || amount.Cmp(decimalZero) != 1 {
+		return errors.New("Order amount should be greater than zero")
+	}
+	g.lock()
+	defer g.unlock()
+
+	if g.Markets[index].Position(account).No < amount.Val {
+		return errors.New("insufficient NO position")
+	}
+	g.convert(account, "", index, amount.Val)
+	return nil
+}
+
+// BestBidSum 返回各结果 YES 最优买价之和（含 NO 卖单隐含的 YES 买价），无套利时不超过 1
+func (g *MarketGroup) BestBidSum() *util.StandardBigDecimal {
+	g.lock()
+	defer g.unlock()
+
+	var sum int64
+	for i := range g.Markets {
+		sum += g.bestBid(i)
+	}
+	return &util.StandardBigDecimal{Val: sum}
+}
+
+// Resolve 以第 winner 个结果为 YES 结算整个市场组，其余结果按 NO 结算，返回各结果市场的结算报告
+func (g *MarketGroup) Resolve(winner int) ([]*SettlementReport, error) {
+	if winner < 0 || winner >= len(g.Markets) {
+		return nil, errors.New("invalid outcome")
+	}
+	g.lock()
+	defer g.unlock()
+
+	if g.Markets[0].Yes.state == StateResolved {
+		return nil, errors.New("market already resolved")
+	}
+	reports := make([]*SettlementReport, len(g.Markets))
+	for i, m := range g.Markets {
+		resolution := ResolutionNo
+		if i == winner {
+			resolution = ResolutionYes
+		}
+		reports[i], _ = m.resolve(resolution)
+	}
+	return reports, nil
+}
+
+// lock 按固定顺序锁定组内全部订单簿
+func (g *MarketGroup) lock() {
+	for _, m := range g.Markets {
+		m.Yes.mutex.Lock()
+		m.No.mutex.Lock()
+	}
+}
+
+// unlock 回收各订单簿本次操作中不再使用的引擎订单 ID 后释放组内全部订单簿的锁
+func (g *MarketGroup) unlock() {
+	for _, m := range g.Markets {
+		m.Yes.releaseIDs()
+		m.No.releaseIDs()
+	}
+	for _, m := range g.Markets {
+		m.No.mutex.Unlock()
+		m.Yes.mutex.Unlock()
+	}
+}
+
+// continuous 判断组内订单簿是否都处于连续撮合阶段
+func (g *MarketGroup) continuous() bool {
+	for _, m := range g.Markets {
+		if m.Yes.state != StateContinuous || m.No.state != StateContinuous {
+			return false
+		}
+	}
+	return true
+}
+
+// routeLegs 返回第 index 个结果 NO 订单跨结果路由的各腿（其余结果 YES 订单簿中对手方向的最优挂单），
+// 任一订单簿无挂单时返回 nil；已触发订单组的兄弟订单先行撤销，与 Taker 同账户的挂单由 fillRoute 按 STP 处理
+func (g *MarketGroup) routeLegs(index int, order *Order) []routeLeg {
+	legs := make([]routeLeg, 0, len(g.Markets)-1)
+	for j, m := range g.Markets {
+		if j == index {
+			continue
+		}
+		ob := m.Yes
+		tree := ob.SellTree
+		if order.Type == Sell {
+			tree = ob.BuyTree
+		}
+		for {
+			node := ob.bestNode(tree, order.Type)
+			if node == nil {
+				return nil
+			}
+			if ob.Arena.Get(node.Head).siblingDone() {
+				ob.cancelMaker(node, node.Head, CancelOrderGroup)
+				if node.Count == 0 {
+					ob.releaseLevel(tree, node)
+				}
+				continue
+			}
+			legs = append(legs, routeLeg{book: ob, tree: tree, node: node, idx: node.Head})
+			break
+		}
+	}
+	return legs
+}
+
+// routePrice 返回第 index 个结果 NO 订单跨结果路由的价格（各腿价格之和），不可用时返回 nil
+func (g *MarketGroup) routePrice(index int, order *Order) *util.StandardBigDecimal {
+	legs := g.routeLegs(index, order)
+	if legs == nil {
+		return nil
+	}
+	var sum int64
+	for _, leg := range legs {
+		sum += leg.book.Arena.Get(leg.idx).Price.Val
+	}
+	return &util.StandardBigDecimal{Val: sum}
+}
+
+// fillRoute 按跨结果路由成交一份组合：各腿以 Maker 价格成交相同数量（调用方已检查价格）
+// 返回 false 表示路由已不可用或 Taker 已停止撮合（被自成交防护撤销或触发熔断）
+func (g *MarketGroup) fillRoute(index int, order *Order) bool {
+	legs := g.routeLegs(index, order)
+	if legs == nil {
+		return false
+	}
+	var sum int64
+	for _, leg := range legs {
+		sum += leg.book.Arena.Get(leg.idx).Price.Val
+	}
+	if g.Markets[index].No.breachesBand(&util.StandardBigDecimal{Val: sum}) {
+		return false
+	}
+	for _, leg := range legs {
+		if leg.book.breachesBand(leg.book.Arena.Get(leg.idx).Price) {
+			return false
+		}
+	}
+	for _, leg := range legs {
+		if isSelfTrade(order, leg.book.Arena.Get(leg.idx)) {
+			// 任一腿与 Taker 同账户时本轮不成交，按 Taker 的 STP 模式处理后重新选择路径
+			cancelled := leg.book.preventSelfTrade(order, leg.node, leg.idx)
+			if leg.node.Count == 0 {
+				leg.book.releaseLevel(leg.tree, leg.node)
+			}
+			return !cancelled
+		}
+	}
+
+	fill := order.Amount.Val
+	for _, leg := range legs {
+		if amount := leg.book.Arena.Get(leg.idx).Amount.Val; amount < fill {
+			fill = amount
+		}
+	}
+
+	// 各腿成交共用 Taker 订单簿的成交 ID；Taker 按各腿价格之和只记一次成交，执行报告由 Taker 所在订单簿生成
+	own := g.Markets[index].No
+	own.seq++
+	id := own.tradeID()
+	for _, leg := range legs {
+		maker := leg.book.Arena.Get(leg.idx)
+		leg.book.onLegTrade(id, maker, order, maker.Price.Val, fill)
+		leg.book.fillResting(leg.node, leg.idx, fill)
+	}
+	order.fill(sum, fill)
+	order.Amount.Val -= fill
+	if own.execListener != nil {
+		own.reportTrade(order, false, id, sum, fill, own.clock())
+	}
+	if len(own.groups.byOrder) > 0 {
+		own.groups.onTrade(order, fill)
+	}
+
+	if order.Account != "" {
+		// 卖出：先把 NO 转换为其余结果的 YES 再卖出；买入：买到的 YES 组合按 NO 记入持仓
+		amount := fill
+		if order.Type == Buy {
+			amount = -fill
+		}
+		g.convert(order.Account, order.ID, index, amount)
+	}
+	return true
+}
+
+// onLegTrade 记录跨结果路由中一条腿的成交：Maker 在本订单簿按其价格成交并生成执行报告，
+// Taker 的累计成交与执行报告由 fillRoute 在 Taker 所在订单簿统一记录（调用方持有锁）
+func (ob *OrderBook) onLegTrade(id string, maker, taker *Order, price, amount int64) {
+	ob.lastPrice = price
+	maker.fill(price, amount)
+	ob.listener.OnTrade(maker.ID, taker.ID, maker.Type, price, amount)
+	var fees *TradeFees
+	if ob.fees != nil {
+		fees = chargeFees(ob, ob, maker, taker, price, price, amount)
+	}
+	ob.seq++
+	ob.publishTrade(id, maker, taker, "", price, price, amount, fees, true, false)
+	if ob.fillHook != nil {
+		ob.fillHook(maker.Account, taker.Account, maker.Type, price, amount)
+	}
+	if len(ob.groups.byOrder) > 0 {
+		ob.groups.onTrade(maker, amount)
+	}
+}
+
+// routeDepth 返回跨结果路由在价格范围内最多可成交的数量（不超过 limit，FOK 预检查）
+func (g *MarketGroup) routeDepth(index int, order *Order, limit int64) int64 {
+	var books [][]auctionLevel
+	for j, m := range g.Markets {
+		if j == index {
+			continue
+		}
+		levels := m.Yes.auctionLevels(oppositeSide(order.Type))
+		if len(levels) == 0 {
+			return 0
+		}
+		books = append(books, levels)
+	}
+
+	// 各腿逐档消耗：每轮取各订单簿当前档位的最小剩余量，直到价格之和不再可成交
+	var depth int64
+	for depth < limit {
+		var sum int64
+		fill := limit - depth
+		for _, levels := range books {
+			sum += levels[0].price.Val
+			if levels[0].volume < fill {
+				fill = levels[0].volume
+			}
+		}
+		if !crosses(order, &util.StandardBigDecimal{Val: sum}) {
+			break
+		}
+		depth += fill
+		for k, levels := range books {
+			levels[0].volume -= fill
+			if levels[0].volume == 0 {
+				if len(levels) == 1 {
+					return depth
+				}
+				books[k] = levels[1:]
+			}
+		}
+	}
+	return depth
+}
+
+// convert 调整持仓：第 index 个结果的 NO 减少 amount，其余各结果的 YES 增加 amount（amount 可为负）
+func (g *MarketGroup) convert(account, orderID string, index int, amount int64) {
+	for j, m := range g.Markets {
+		m.ledgerMu.Lock()
+		position, ok := m.positions[account]
+		if !ok {
+			position = &AccountSettlement{}
+			m.positions[account] = position
+		}
+		if j == index {
+			position.No -= amount
+		} else {
+			position.Yes += amount
+		}
+		m.ledgerMu.Unlock()
+	}
+	if g.conversionListener != nil {
+		g.conversionListener.OnConversion(account, orderID, index, amount)
+	}
+}
+
+// bestBid 返回第 i 个结果的 YES 最优买价（YES 买单与 NO 卖单隐含买价中的较高者），无买盘时为 0
+func (g *MarketGroup) bestBid(i int) int64 {
+	m := g.Markets[i]
+	var bid int64
+	if best := m.Yes.bestPrice(Sell, m.Yes.BuyTree); best != nil {
+		bid = best.Val
+	}
+	if ask := m.No.bestPrice(Buy, m.No.SellTree); ask != nil && util.SCALE-ask.Val > bid {
+		bid = util.SCALE - ask.Val
+	}
+	return bid
+}
+
+// bidsConsistent 判断订单剩余部分挂出后各结果 YES 最优买价之和是否仍不超过 1
+// 只有 YES 买单与 NO 卖单（隐含 YES 买价）会抬高买价
+func (g *MarketGroup) bidsConsistent(index int, outcome Outcome, order *Order) bool {
+	var bid int64
+	switch {
+	case outcome == OutcomeYes && order.Type == Buy:
+		bid = order.Price.Val
+	case outcome == OutcomeNo && order.Type == Sell:
+		bid = util.SCALE - order.Price.Val
+	default:
+		return true
+	}
+
+	sum := bid
+	for i := range g.Markets {
+		current := g.bestBid(i)
+		if i != index {
+			sum += current
+		} else if current > bid {
+			sum += current - bid
+		}
+	}
+	return sum <= util.SCALE
+}
@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"testing"
+)
+
+type conversionListener struct {
+	binaryListener
+	Converted map[string]int64
+}
+
+func (l *conversionListener) OnConversion(account, orderID string, outcome int, amount int64) {
+	if l.Converted == nil {
+		l.Converted = map[string]int64{}
+	}
+	l.Converted[account] += amount
+}
+
+func TestMarketGroupBuyNoViaYesRoute(t *testing.T) {
+	listener := &conversionListener{}
+	g, err := NewMarketGroup(3, listener)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Markets[1].Process(OutcomeYes, newBinaryOrder("b", "bob", Sell, "10.0", "0.3"))
+	g.Markets[2].Process(OutcomeYes, newBinaryOrder("c", "carol", Sell, "4.0", "0.2"))
+
+	// 买入 NO_0 @0.6：YES_1 + YES_2 = 0.5，按组合成交 4 份，剩余挂单
+	g.Process(0, OutcomeNo, newBinaryOrder("a", "alice", Buy, "6.0", "0.6"))
+	if len(listener.Trades) != 2 || listener.Trades[0].Amount != DecimalBig("4.0").Val || listener.Trades[1].Amount != DecimalBig("4.0").Val {
+		t.Fatalf("expected two route legs of 4 (have: %+v)", listener.Trades)
+	}
+	rest := g.Markets[0].No.Arena.Get(indexOf(g.Markets[0].No, "a"))
+	if rest.Amount.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatal("remainder should rest once the route is exhausted")
+	}
+	if g.Markets[0].Position("alice").No != DecimalBig("4.0").Val || g.Markets[1].Position("alice").Yes != 0 {
+		t.Fatalf("route fill should be booked as NO (have: %+v)", g.Markets[0].Position("alice"))
+	}
+	if listener.Converted["alice"] != -DecimalBig("4.0").Val {
+		t.Fatal("route fill should be reported as a conversion")
+	}
+}
+
+func TestMarketGroupSellNoAndConvert(t *testing.T) {
+	listener := &conversionListener{}
+	g, _ := NewMarketGroup(3, listener)
+
+	// alice 以 0.6 买入 NO_0（对手为 NO_0 卖单），再按组合卖给 YES_1 / YES_2 买单
+	g.Process(0, OutcomeNo, newBinaryOrder("s", "sam", Sell, "5.0", "0.6"))
+	g.Process(0, OutcomeNo, newBinaryOrder("a1", "alice", Buy, "5.0", "0.6"))
+	g.Process(1, OutcomeYes, newBinaryOrder("b", "bob", Buy, "2.0", "0.35"))
+	g.Process(2, OutcomeYes, newBinaryOrder("c", "carol", Buy, "5.0", "0.3"))
+
+	g.Process(0, OutcomeNo, newBinaryOrder("a2", "alice", Sell, "2.0", "0.65"))
+	if g.Markets[0].Position("alice").No != DecimalBig("3.0").Val || g.Markets[1].Position("alice").Yes != 0 || g.Markets[2].Position("alice").Yes != 0 {
+		t.Fatal("selling NO via the YES route should reduce the NO position")
+	}
+	if g.Markets[1].Position("bob").Yes != DecimalBig("2.0").Val {
+		t.Fatal("route buyers should receive YES")
+	}
+
+	if err := g.Convert("alice", 0, DecimalBig("5.0")); err == nil {
+		t.Fatal("cannot convert more NO than held")
+	}
+	if err := g.Convert("alice", 0, DecimalBig("3.0")); err != nil {
+		t.Fatal(err)
+	}
+	if g.Markets[0].Position("alice").No != 0 || g.Markets[1].Position("alice").Yes != DecimalBig("3.0").Val || g.Markets[2].Position("alice").Yes != DecimalBig("3.0").Val {
+		t.Fatal("NO should convert into YES on every other outcome")
+	}
+
+	reports, err := g.Resolve(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reports[2].Accounts["alice"].Payout != DecimalBig("3.0").Val || reports[2].Resolution != ResolutionYes || reports[0].Resolution != ResolutionNo {
+		t.Fatalf("unexpected settlement %+v", reports[2])
+	}
+}
+
+func TestMarketGroupBidConsistency(t *testing.T) {
+	listener := &conversionListener{}
+	g, _ := NewMarketGroup(3, listener)
+	g.Process(0, OutcomeYes, newBinaryOrder("y0", "a", Buy, "1.0", "0.5"))
+	g.Process(1, OutcomeYes, newBinaryOrder("y1", "b", Buy, "1.0", "0.3"))
+
+	g.Process(2, OutcomeYes, newBinaryOrder("y2", "c", Buy, "1.0", "0.25"))
+	if listener.Cancelled[len(listener.Cancelled)-1] != "y2" {
+		t.Fatal("bid pushing the sum above 1 should not rest")
+	}
+	g.Process(2, OutcomeYes, newBinaryOrder("y3", "c", Buy, "1.0", "0.2"))
+	if g.BestBidSum().Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("bid sum should stay at 1 (have: %v)", g.BestBidSum())
+	}
+
+	post := newBinaryOrder("p", "d", Buy, "1.0", "0.6")
+	post.PostOnly = PostOnlyReject
+	g.Process(0, OutcomeYes, post)
+	if listener.Rejected["p"] != RejectBidsExceedOne {
+		t.Fatal("post-only bid should be rejected when inconsistent")
+	}
+
+	// NO_2 卖 0.75 隐含 YES_2 买价 0.25：其余结果买价之和 0.8 可成交，按路由卖出而不是挂单
+	g.Process(2, OutcomeNo, newBinaryOrder("n2", "c", Sell, "1.0", "0.75"))
+	if len(listener.Trades) != 2 || g.Markets[2].Position("c").No != -DecimalBig("1.0").Val {
+		t.Fatalf("NO sell should route into the YES bids (have: %+v)", listener.Trades)
+	}
+	if _, err := NewMarketGroup(1, listener); err == nil {
+		t.Fatal("single outcome group should fail")
+	}
+}
+
+func TestMarketGroupFOKRouteDepth(t *testing.T) {
+	listener := &conversionListener{}
+	g, _ := NewMarketGroup(3, listener)
+	g.Process(1, OutcomeYes, newBinaryOrder("b1", "bob", Sell, "2.0", "0.3"))
+	g.Process(1, OutcomeYes, newBinaryOrder("b2", "bob", Sell, "3.0", "0.35"))
+	g.Process(2, OutcomeYes, newBinaryOrder("c1", "carol", Sell, "4.0", "0.2"))
+
+	fok := newBinaryOrder("a", "alice", Buy, "5.0", "0.5")
+	fok.TimeInForce = FOK
+	g.Process(0, OutcomeNo, fok)
+	if len(listener.Trades) != 0 {
+		t.Fatal("FOK should not trade when route depth is short")
+	}
+
+	fok = newBinaryOrder("a2", "alice", Buy, "4.0", "0.55")
+	fok.TimeInForce = FOK
+	g.Process(0, OutcomeNo, fok)
+	if g.Markets[0].Position("alice").No != DecimalBig("4.0").Val {
+		t.Fatalf("FOK should fill across route levels (have: %+v)", listener.Trades)
+	}
+}
+
+type groupSTPListener struct {
+	binaryListener
+	Prevented []string
+}
+
+func (l *groupSTPListener) OnSelfTradePrevented(makerID, takerID string, mode STPMode) {
+	l.Prevented = append(l.Prevented, makerID+"/"+takerID)
+}
+
+func TestMarketGroupRouteSelfTrade(t *testing.T) {
+	for _, mode := range []STPMode{STPCancelNewest, STPCancelOldest} {
+		listener := &groupSTPListener{}
+		g, _ := NewMarketGroup(3, listener)
+		g.Process(1, OutcomeYes, newBinaryOrder("b", "alice", Sell, "2.0", "0.3"))
+		g.Process(2, OutcomeYes, newBinaryOrder("c", "carol", Sell, "2.0", "0.2"))
+
+		taker := newBinaryOrder("a", "alice", Buy, "1.0", "0.6")
+		taker.STP = mode
+		g.Process(0, OutcomeNo, taker)
+		if len(listener.Prevented) != 1 || listener.Prevented[0] != "b/a" || len(listener.Trades) != 0 {
+			t.Fatalf("%s: route self trade should be prevented (have: %v, %+v)", mode, listener.Prevented, listener.Trades)
+		}
+		_, resting := g.Markets[0].No.lookup("a")
+		_, makerResting := g.Markets[1].Yes.lookup("b")
+		if mode == STPCancelNewest && (resting || !makerResting) || mode == STPCancelOldest && (!resting || makerResting) {
+			t.Fatalf("%s: unexpected book (taker resting: %v, maker resting: %v)", mode, resting, makerResting)
+		}
+		if mode == STPCancelOldest && len(g.Markets[1].Yes.GetOrders(0).Sells) != 0 {
+			t.Fatal("emptied route level should be released")
+		}
+	}
+}
+
+func TestMarketGroupRouteStopsAtPriceBand(t *testing.T) {
+	listener := &conversionListener{}
+	g, _ := NewMarketGroup(3, listener)
+	g.Markets[2].Yes.SetPriceBands(PriceBands{Static: DecimalBig("5.0"), Reference: DecimalBig("0.1")})
+	g.Process(1, OutcomeYes, newBinaryOrder("b", "bob", Sell, "2.0", "0.3"))
+	g.Process(2, OutcomeYes, newBinaryOrder("c", "carol", Sell, "2.0", "0.2"))
+
+	g.Process(0, OutcomeNo, newBinaryOrder("a", "alice", Buy, "1.0", "0.6"))
+	if len(listener.Trades) != 0 || g.Markets[2].Yes.TradingState() != StateHalted {
+		t.Fatalf("route leg outside the band should halt before filling (have: %+v)", listener.Trades)
+	}
+	if _, ok := g.Markets[0].No.lookup("a"); !ok {
+		t.Fatal("taker should rest once the route is halted")
+	}
+}
+
+type routeExecListener struct {
+	binaryListener
+	execListener
+}
+
+func TestMarketGroupRouteTakerReport(t *testing.T) {
+	listener := &routeExecListener{}
+	g, err := NewMarketGroup(3, listener)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Markets[1].Process(OutcomeYes, newBinaryOrder("b", "bob", Sell, "5.0", "0.1"))
+	g.Markets[2].Process(OutcomeYes, newBinaryOrder("c", "carol", Sell, "5.0", "0.2"))
+	g.Process(0, OutcomeNo, newBinaryOrder("a", "alice", Buy, "10.0", "0.6"))
+
+	// 跨结果路由成交 5 份：Taker 只记一次成交，价格为各腿之和
+	var trades []ExecutionReport
+	for _, report := range listener.Reports {
+		if report.OrderID == "a" && report.ExecType == ExecTrade {
+			trades = append(trades, report)
+		}
+	}
+	if len(trades) != 1 || trades[0].LastPrice != DecimalBig("0.3").Val || trades[0].LastAmount != DecimalBig("5.0").Val {
+		t.Fatalf("route fill should be reported once at the summed price (have: %+v)", trades)
+	}
+	report := listener.last("a")
+	if report.ExecType != ExecNew || report.Status != StatusPartiallyFilled || report.FilledAmount != DecimalBig("5.0").Val ||
+		report.LeavesAmount != DecimalBig("5.0").Val || report.AvgPrice != DecimalBig("0.3").Val {
+		t.Fatalf("resting remainder should report the route fill once (have: %+v)", report)
+	}
+	if maker := listener.last("b"); maker.ExecType != ExecTrade || maker.Status != StatusFilled || maker.TradeID != trades[0].TradeID {
+		t.Fatalf("leg maker should be reported from its own book under the route trade ID (have: %+v)", maker)
+	}
+}
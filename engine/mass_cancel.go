@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"github.com/goovo/matching-engine/util"
+)
+
+// MassCancelFilter 批量撤单条件，零值字段表示不过滤（全部为零值时撤销整个订单簿）
+type MassCancelFilter struct {
+	// 账户（所有者）ID
+	Account string
+	// 订单方向，为空时撤销双边
+	Side Side
+	// 价格位于该价格或更激进一侧的订单：买单 >= Price，卖单 <= Price；
+	// 设置价格时不撤销未触发的条件单
+	Price *util.StandardBigDecimal
+}
+
+// MassCancelResult 批量撤单结果
+type MassCancelResult struct {
+	Buys  int // 撤销的买单数量（含条件单）
+	Sells int // 撤销的卖单数量（含条件单）
+}
+
+// MassCancel 按条件批量撤销订单簿（及条件单簿）中的订单，每笔订单触发一次 OnOrderCancelled
+func (ob *OrderBook) MassCancel(filter MassCancelFilter) MassCancelResult {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+
+	result := ob.massCancel(filter, CancelMassCancel)
+	// 撤销订单组的兄弟订单
+	ob.triggerStops()
+	return result
+}
+
+// massCancel 按条件批量撤单并上报撤单原因 reason（调用方持有锁）
+func (ob *OrderBook) massCancel(filter MassCancelFilter, reason CancelReason) MassCancelResult {
+	var result MassCancelResult
+	if filter.Side != Sell {
+		result.Buys = ob.massCancelSide(ob.BuyTree, Buy, filter, reason)
+	}
+	if filter.Side != Buy {
+		result.Sells = ob.massCancelSide(ob.SellTree, Sell, filter, reason)
+	}
+
+	if filter.Price == nil {
+		// 按条件单簿的触发顺序（买方在前）撤销，先收集再撤销，避免遍历过程中修改队列
+		var matched []*stopOrder
+		for _, queue := range [][]*stopOrder{ob.stops.buys, ob.stops.sells} {
+			for _, stop := range queue {
+				if filter.Side != "" && stop.order.Type != filter.Side || filter.Account != "" && stop.order.Account != filter.Account {
+					continue
+				}
+				matched = append(matched, stop)
+			}
+		}
+		for _, stop := range matched {
+			ob.stops.remove(stop)
+			ob.retire(stop.order.EID)
+			ob.cancelWithReason(&stop.order, reason)
+			if stop.order.Type == Buy {
+				result.Buys++
+			} else {
+				result.Sells++
+			}
+		}
+	}
+	return result
+}
+
+// massCancelSide 撤销单边订单簿中满足条件的订单，返回撤销数量
+func (ob *OrderBook) massCancelSide(tree PriceLevels, side Side, filter MassCancelFilter, reason CancelReason) int {
+	// 先收集再撤销，避免遍历过程中修改价格树
+	var matched []IndexType
+	var limit int64
+	if filter.Price != nil {
+		limit = filter.Price.Val
+	}
+	tree.Walk(side == Sell, func(price int64, node *OrderNode) bool {
+		if filter.Price != nil && (side == Buy && price < limit || side == Sell && price > limit) {
+			return false
+		}
+		for idx := node.Head; idx != NullIndex; idx = ob.Arena.Get(idx).Next {
+			order := ob.Arena.Get(idx)
+			if filter.Account != "" && order.Account != filter.Account {
+				continue
+			}
+			if filter.Price != nil && (side == Buy && order.Price.Cmp(filter.Price) == -1 || side == Sell && order.Price.Cmp(filter.Price) == 1) {
+				continue
+			}
+			matched = append(matched, idx)
+		}
+		return true
+	})
+
+	for _, idx := range matched {
+		order := ob.Arena.Get(idx)
+		ob.removeIndex(idx)
+		ob.cancelWithReason(order, reason)
+	}
+	return len(matched)
+}
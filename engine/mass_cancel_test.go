@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"testing"
+)
+
+func newMassCancelBook() (*OrderBook, *MockListener) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newAccountOrder("a-b1", Buy, "1.0", "99.0", "A", ""))
+	ob.Process(newAccountOrder("a-b2", Buy, "1.0", "98.0", "A", ""))
+	ob.Process(newAccountOrder("b-b1", Buy, "1.0", "99.0", "B", ""))
+	ob.Process(newAccountOrder("a-s1", Sell, "1.0", "101.0", "A", ""))
+	ob.Process(newAccountOrder("a-s2", Sell, "1.0", "102.0", "A", ""))
+	ob.Process(newAccountOrder("b-s1", Sell, "1.0", "101.0", "B", ""))
+	stop := newStop("a-stop", Buy, "1.0", "110.0", "105.0")
+	stop.Account = "A"
+	ob.Process(stop)
+	stop = newStop("b-stop", Buy, "1.0", "110.0", "104.0")
+	stop.Account = "B"
+	ob.Process(stop)
+	return ob, listener
+}
+
+func TestMassCancel(t *testing.T) {
+	var tests = []struct {
+		name      string
+		filter    MassCancelFilter
+		result    MassCancelResult
+		cancelled []string
+	}{
+		// 撤单顺序：买盘价格-时间优先、卖盘价格-时间优先、条件单按触发顺序
+		{"all", MassCancelFilter{}, MassCancelResult{5, 3}, []string{"a-b1", "b-b1", "a-b2", "a-s1", "b-s1", "a-s2", "b-stop", "a-stop"}},
+		{"account", MassCancelFilter{Account: "A"}, MassCancelResult{3, 2}, []string{"a-b1", "a-b2", "a-s1", "a-s2", "a-stop"}},
+		{"side", MassCancelFilter{Side: Sell}, MassCancelResult{0, 3}, []string{"a-s1", "b-s1", "a-s2"}},
+		{"buy through price", MassCancelFilter{Side: Buy, Price: DecimalBig("99.0")}, MassCancelResult{2, 0}, []string{"a-b1", "b-b1"}},
+		{"sell through price", MassCancelFilter{Side: Sell, Price: DecimalBig("101.5"), Account: "A"}, MassCancelResult{0, 1}, []string{"a-s1"}},
+	}
+
+	for _, tt := range tests {
+		ob, listener := newMassCancelBook()
+		result := ob.MassCancel(tt.filter)
+		if result != tt.result {
+			t.Fatalf("%s: unexpected counts (have: %+v, want: %+v)", tt.name, result, tt.result)
+		}
+		if len(listener.Cancelled) != len(tt.cancelled) {
+			t.Fatalf("%s: unexpected cancels (have: %v, want: %v)", tt.name, listener.Cancelled, tt.cancelled)
+		}
+		for i, id := range tt.cancelled {
+			if listener.Cancelled[i] != id {
+				t.Fatalf("%s: unexpected cancels (have: %v, want: %v)", tt.name, listener.Cancelled, tt.cancelled)
+			}
+			if _, ok := ob.lookup(id); ok {
+				t.Fatalf("%s: %s should be removed from book", tt.name, id)
+			}
+		}
+	}
+}
+
+func TestMassCancelEmptiesLevels(t *testing.T) {
+	ob, _ := newMassCancelBook()
+	ob.MassCancel(MassCancelFilter{})
+	if ob.orders.size() != 0 || len(ob.stops.index) != 0 {
+		t.Fatal("book should be empty")
+	}
+	if ob.bestPrice(Buy, ob.SellTree) != nil || ob.bestPrice(Sell, ob.BuyTree) != nil {
+		t.Fatal("price levels should be removed")
+	}
+
+	// 撤单后订单簿仍可正常撮合
+	listener := &MockListener{}
+	ob.listener = listener
+	ob.Process(*NewOrder("s", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.Trades) != 1 {
+		t.Fatal("book should keep matching after mass cancel")
+	}
+}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"github.com/goovo/matching-engine/util"
+)
+
+// MatchingPolicy 价格档位内的成交分配策略
+type MatchingPolicy interface {
+	// Allocate 将 Taker 数量 amount 分配给同一价格档位内按时间顺序排列的 Maker（makers 为各自可成交数量），
+	// 结果写入与 makers 等长的 alloc；分配总量为 min(amount, sum(makers))，且每项不超过对应 Maker 数量
+	Allocate(makers []int64, amount int64, alloc []int64)
+}
+
+// FIFOPolicy 价格-时间优先（默认策略）
+type FIFOPolicy struct{}
+
+// Allocate 实现 MatchingPolicy 接口：按时间顺序依次吃满 Maker
+func (FIFOPolicy) Allocate(makers []int64, amount int64, alloc []int64) {
+	for i, maker := range makers {
+		take := maker
+		if take > amount {
+			take = amount
+		}
+		alloc[i] = take
+		amount -= take
+	}
+}
+
+// ProRataPolicy 按挂单数量比例分配
+// TopOrder 为 true 时档位内最早的订单优先吃满，剩余数量再按比例分配给其余订单；
+// 按比例分得的数量小于 MinAllocation 时不分配，取整与最小分配剩下的数量按时间顺序补足
+type ProRataPolicy struct {
+	TopOrder      bool
+	MinAllocation *util.StandardBigDecimal
+}
+
+// Allocate 实现 MatchingPolicy 接口
+func (p ProRataPolicy) Allocate(makers []int64, amount int64, alloc []int64) {
+	var total int64
+	for i, maker := range makers {
+		total += maker
+		alloc[i] = 0
+	}
+	if amount >= total {
+		copy(alloc, makers)
+		return
+	}
+
+	start := 0
+	if p.TopOrder && len(makers) > 0 {
+		alloc[0] = makers[0]
+		if alloc[0] > amount {
+			alloc[0] = amount
+		}
+		amount -= alloc[0]
+		total -= makers[0]
+		start = 1
+	}
+
+	remaining := amount
+	for i := start; i < len(makers); i++ {
+		share := util.MulDivFloor(amount, makers[i], total)
+		if p.MinAllocation != nil && share < p.MinAllocation.Val {
+			share = 0
+		}
+		alloc[i] = share
+		remaining -= share
+	}
+	// 余量按时间顺序分配
+	for i := start; i < len(makers) && remaining > 0; i++ {
+		take := makers[i] - alloc[i]
+		if take > remaining {
+			take = remaining
+		}
+		alloc[i] += take
+		remaining -= take
+	}
+}
+
+// BookOption 订单簿构造选项
+type BookOption func(*OrderBook)
+
+// WithMatchingPolicy 设置价格档位内的成交分配策略
+func WithMatchingPolicy(policy MatchingPolicy) BookOption {
+	return func(ob *OrderBook) {
+		if _, ok := policy.(FIFOPolicy); ok {
+			// FIFO 走逐笔撮合的快速路径
+			policy = nil
+		}
+		ob.policy = policy
+	}
+}
+
+// matchLevel 按撮合策略在单个价格档位内分配 Taker 数量并成交（调用方已检查价格）
+// 返回 true 表示 Taker 已停止撮合（数量用完、被自成交防护撤销或剩余金额不足）
+func (ob *OrderBook) matchLevel(order *Order, node *OrderNode) bool {
+	if order.Account != "" || len(ob.groups.byOrder) > 0 {
+		// 已触发订单组的兄弟订单与同账户 Maker 不参与分配
+		for idx := node.Head; idx != NullIndex; {
+			maker := ob.Arena.Get(idx)
+			next := maker.Next
+			if maker.siblingDone() {
+				ob.cancelMaker(node, idx, CancelOrderGroup)
+			} else if isSelfTrade(order, maker) && ob.preventSelfTrade(order, node, idx) {
+				return true
+			}
+			idx = next
+		}
+		if node.Count == 0 {
+			return false
+		}
+	}
+	if order.Funds != nil && !ob.affordQuote(order, ob.Arena.Get(node.Head).Price) {
+		order.cancelReason = CancelInsufficientFunds
+		return true
+	}
+
+	ob.levelOrders = ob.levelOrders[:0]
+	ob.levelAmounts = ob.levelAmounts[:0]
+	for idx := node.Head; idx != NullIndex; idx = ob.Arena.Get(idx).Next {
+		ob.levelOrders = append(ob.levelOrders, idx)
+		ob.levelAmounts = append(ob.levelAmounts, ob.Arena.Get(idx).Amount.Val)
+	}
+	if cap(ob.levelAllocs) < len(ob.levelAmounts) {
+		ob.levelAllocs = make([]int64, len(ob.levelAmounts))
+	}
+	alloc := ob.levelAllocs[:len(ob.levelAmounts)]
+	ob.policy.Allocate(ob.levelAmounts, order.Amount.Val, alloc)
+
+	for i, idx := range ob.levelOrders {
+		if alloc[i] <= 0 {
+			continue
+		}
+		maker := ob.Arena.Get(idx)
+		fill := &util.StandardBigDecimal{Val: alloc[i]}
+		ob.onTrade(maker, order, maker.Price.Val, fill.Val)
+		order.Amount.SubMut(fill)
+
+		if maker.Amount.Cmp(fill) == 0 {
+			// 移出订单簿（冰山单则刷新后排到队尾）
+			ob.removeFilledMaker(node, idx)
+		} else {
+			maker.Amount.SubMut(fill)
+			node.Volume.SubMut(fill)
+		}
+	}
+	return order.Amount.Val == 0
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyAllocate(t *testing.T) {
+	var tests = []struct {
+		name   string
+		policy MatchingPolicy
+		makers []int64
+		amount int64
+		alloc  []int64
+	}{
+		{"fifo", FIFOPolicy{}, []int64{30, 50, 20}, 60, []int64{30, 30, 0}},
+		{"fifo sweep", FIFOPolicy{}, []int64{30, 50, 20}, 200, []int64{30, 50, 20}},
+		{"pro-rata", ProRataPolicy{}, []int64{20, 60, 20}, 50, []int64{10, 30, 10}},
+		{"pro-rata residual", ProRataPolicy{}, []int64{10, 10, 10}, 10, []int64{4, 3, 3}},
+		{"pro-rata sweep", ProRataPolicy{}, []int64{10, 10}, 30, []int64{10, 10}},
+		{"min allocation", ProRataPolicy{MinAllocation: &decimalMin}, []int64{5, 90, 5}, 40, []int64{4, 36, 0}},
+		{"top order", ProRataPolicy{TopOrder: true}, []int64{10, 40, 20}, 40, []int64{10, 20, 10}},
+		{"top order only", ProRataPolicy{TopOrder: true}, []int64{50, 40, 20}, 40, []int64{40, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		alloc := make([]int64, len(tt.makers))
+		tt.policy.Allocate(tt.makers, tt.amount, alloc)
+		if !reflect.DeepEqual(alloc, tt.alloc) {
+			t.Fatalf("%s: unexpected allocation (have: %v, want: %v)", tt.name, alloc, tt.alloc)
+		}
+	}
+}
+
+var decimalMin = *DecimalBig("0.00000005")
+
+func TestProRataOrderBook(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener, WithMatchingPolicy(ProRataPolicy{}))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("3.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("2.0"), DecimalBig("100.0")))
+	want := []MockTrade{
+		{"s1", "b1", DecimalBig("100.0").Val, DecimalBig("0.5").Val},
+		{"s2", "b1", DecimalBig("100.0").Val, DecimalBig("1.5").Val},
+	}
+	if !reflect.DeepEqual(listener.Trades, want) {
+		t.Fatalf("unexpected trades (have: %+v)", listener.Trades)
+	}
+	if s2 := ob.Arena.Get(indexOf(ob, "s2")); s2.Amount.Cmp(DecimalBig("1.5")) != 0 || ob.Levels.Get(s2.Node).Volume.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatalf("unexpected maker state (have: %s, volume %s)", s2.Amount, ob.Levels.Get(s2.Node).Volume.String())
+	}
+
+	// 扫过整档后在下一档继续撮合，剩余部分挂单
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("3.5"), DecimalBig("101.0")))
+	if len(listener.Trades) != 5 || ob.orders.size() != 1 {
+		t.Fatalf("sweep should consume both levels (have: %+v)", listener.Trades)
+	}
+	if b2 := ob.Arena.Get(indexOf(ob, "b2")); b2.Amount.Cmp(DecimalBig("0.5")) != 0 {
+		t.Fatalf("remainder should rest (have: %s)", b2.Amount)
+	}
+}
+
+func TestProRataMarketOrder(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener, WithMatchingPolicy(ProRataPolicy{TopOrder: true}))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("2.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("b3", Buy, DecimalBig("2.0"), DecimalBig("100.0")))
+
+	ob.ProcessMarket(*NewOrder("s", Sell, DecimalBig("3.0"), DecimalBig("1.0")))
+	amounts := map[string]int64{}
+	for _, trade := range listener.Trades {
+		amounts[trade.MakerID] = trade.Amount
+	}
+	want := map[string]int64{"b1": DecimalBig("1.0").Val, "b2": DecimalBig("1.0").Val, "b3": DecimalBig("1.0").Val}
+	if !reflect.DeepEqual(amounts, want) {
+		t.Fatalf("unexpected allocation (have: %v)", amounts)
+	}
+}
+
+func TestFIFOPolicyOption(t *testing.T) {
+	ob := NewOrderBook(&MockListener{}, WithMatchingPolicy(FIFOPolicy{}))
+	if ob.policy != nil {
+		t.Fatal("FIFO policy should use the default matching path")
+	}
+}
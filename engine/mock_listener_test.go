@@ -8,7 +8,9 @@ type MockTrade struct {
 }
 
 type MockListener struct {
-	Trades []MockTrade
+	Trades    []MockTrade
+	Accepted  []string
+	Cancelled []string
 }
 
 func (l *MockListener) OnTrade(makerID, takerID string, side Side, price, amount int64) {
@@ -20,5 +22,10 @@ func (l *MockListener) OnTrade(makerID, takerID string, side Side, price, amount
 	})
 }
 
-func (l *MockListener) OnOrderAccepted(id string) {}
-func (l *MockListener) OnOrderCancelled(id string) {}
+func (l *MockListener) OnOrderAccepted(id string) {
+	l.Accepted = append(l.Accepted, id)
+}
+
+func (l *MockListener) OnOrderCancelled(id string) {
+	l.Cancelled = append(l.Cancelled, id)
+}
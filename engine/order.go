@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/goovo/matching-engine/util"
@@ -17,14 +16,47 @@ type Order struct {
 	ID     string                   `json:"id"`     // validate:"required"`
 	Type   Side                     `json:"type"`   //  validate:"side_validate"`
 
+	// 有效期类型，零值按 GTC 处理
+	TimeInForce TimeInForce `json:"time_in_force"`
+	// 只做 Maker 模式，零值为普通订单
+	PostOnly PostOnlyMode `json:"post_only"`
+	// 冰山单每次显示的数量，为 nil 时为普通订单
+	DisplayAmount *util.StandardBigDecimal `json:"display_amount"`
+	// 条件单触发价，非 nil 时订单在最新成交价穿越该价格后才进入撮合
+	StopPrice *util.StandardBigDecimal `json:"stop_price"`
+	// 账户（所有者）ID，非空时启用自成交防护
+	Account string `json:"account"`
+	// 自成交防护模式，零值按 cancel_newest 处理
+	STP STPMode `json:"stp"`
+	// 市价单按报价币种金额下单（如 "花 500 USDT"），非 nil 时忽略 Amount
+	Funds *util.StandardBigDecimal `json:"funds"`
+	// 市价单保护价：买单不高于、卖单不低于该价格成交
+	ProtectionPrice *util.StandardBigDecimal `json:"protection_price"`
+	// 市价单相对对手最优价的最大滑点（百分比，如 0.5 表示 0.5%）
+	MaxSlippage *util.StandardBigDecimal `json:"max_slippage"`
+
+	// 冰山单挂单后的隐藏储备，由引擎维护
+	hidden *util.StandardBigDecimal
+	// 市价单本次撮合的价格上限（买）/下限（卖），由保护价与最大滑点计算
+	priceLimit *util.StandardBigDecimal
+	// 撮合结束后剩余部分的撤单原因
+	cancelReason CancelReason
+	// 所属的 OCO / 括号单订单组
+	group *orderGroup
+	// 委托数量（按金额下单的市价单为 0）及累计成交数量、成交额，由引擎维护
+	quantity int64
+	filled   int64
+	notional int64
+
 	// 链表索引 (Arena Index)
 	Next IndexType `json:"-"`
 	Prev IndexType `json:"-"`
 	
-	// 反向指针，指向所属的 OrderNode
-	// 注意：这里依然使用指针，因为 OrderNode 目前不在 Arena 中
-	// 如果需要极致性能，OrderNode 也应该进入 Arena，但目前主要瓶颈是 Order 链表遍历
-	Node *OrderNode `json:"-"`
+	// 所属价格档位 (LevelArena Index)
+	Node IndexType `json:"-"`
+	// 引擎订单 ID：由订单簿驻留字符串 ID 分配（server 在入口处驻留后随订单传入，为 0 时由订单簿驻留），
+	// 只作为订单簿内部的索引键，仅在订单挂单或未触发期间唯一，订单离开订单簿后可能分配给其他订单
+	EID uint64 `json:"-"`
 }
 
 // NewOrder 返回 *Order (堆分配，用于 API 边界)
@@ -36,6 +68,7 @@ func NewOrder(id string, orderType Side, amount, price *util.StandardBigDecimal)
 		Price:  price,
 		Next:   NullIndex,
 		Prev:   NullIndex,
+		Node:   NullIndex,
 	}
 }
 
@@ -48,6 +81,7 @@ func (order *Order) FromJSON(msg []byte) error {
 	// 初始化索引
 	order.Next = NullIndex
 	order.Prev = NullIndex
+	order.Node = NullIndex
 	return nil
 }
 
@@ -59,10 +93,7 @@ func (order *Order) ToJSON() ([]byte, error) {
 
 // String 实现 Stringer 接口
 func (order *Order) String() string {
-	amount := order.Amount.Float64()
-	price := order.Price.Float64()
-
-	return fmt.Sprintf("\"%s\":\n\tside: %v\n\tquantity: %s\n\tprice: %s\n", order.ID, order.Type, strconv.FormatFloat(amount, 'f', -1, 64), strconv.FormatFloat(price, 'f', -1, 64))
+	return fmt.Sprintf("\"%s\":\n\tside: %v\n\tquantity: %s\n\tprice: %s\n", order.ID, order.Type, order.Amount.String(), order.Price.String())
 }
 
 // UnmarshalJSON 实现 json.Unmarshaler 接口
@@ -72,6 +103,17 @@ func (order *Order) UnmarshalJSON(data []byte) error {
 		ID     string `json:"id"`     // validate:"required"`
 		Amount string `json:"amount"` // validate:"required"`
 		Price  string `json:"price"`  // validate:"required"`
+
+		TimeInForce   TimeInForce  `json:"time_in_force"`
+		PostOnly      PostOnlyMode `json:"post_only"`
+		DisplayAmount string       `json:"display_amount"`
+		StopPrice     string       `json:"stop_price"`
+		Account       string       `json:"account"`
+		STP           STPMode      `json:"stp"`
+		Funds         string       `json:"funds"`
+
+		ProtectionPrice string `json:"protection_price"`
+		MaxSlippage     string `json:"max_slippage"`
 	}{}
 
 	if err := json.Unmarshal(data, &obj); err != nil {
@@ -92,22 +134,61 @@ func (order *Order) UnmarshalJSON(data []byte) error {
 		fmt.Println("price", order.Price, err.Error())
 		return errors.New("invalid order price")
 	}
+	if obj.Funds != "" {
+		order.Funds, err = util.NewDecimalFromString(obj.Funds)
+		if err != nil || order.Funds.Val <= 0 {
+			return errors.New("invalid order funds")
+		}
+		// 按金额下单时数量可以不填
+		if obj.Amount == "" {
+			obj.Amount = "0"
+		}
+	}
+	if obj.ProtectionPrice != "" {
+		order.ProtectionPrice, err = util.NewDecimalFromString(obj.ProtectionPrice)
+		if err != nil || order.ProtectionPrice.Val <= 0 {
+			return errors.New("invalid order protection price")
+		}
+	}
+	if obj.MaxSlippage != "" {
+		order.MaxSlippage, err = util.NewDecimalFromString(obj.MaxSlippage)
+		if err != nil || order.MaxSlippage.Val < 0 {
+			return errors.New("invalid order max slippage")
+		}
+	}
 	order.Amount, err = util.NewDecimalFromString(obj.Amount) //.Quantize(8)
 	if err != nil {
 		return errors.New("invalid order amount")
 	}
 
+	if obj.DisplayAmount != "" {
+		order.DisplayAmount, err = util.NewDecimalFromString(obj.DisplayAmount)
+		if err != nil || order.DisplayAmount.Val <= 0 {
+			return errors.New("invalid order display amount")
+		}
+	}
+
+	if obj.StopPrice != "" {
+		order.StopPrice, err = util.NewDecimalFromString(obj.StopPrice)
+		if err != nil || order.StopPrice.Val <= 0 {
+			return errors.New("invalid order stop price")
+		}
+	}
+
 	order.Type = obj.Type
 	order.ID = obj.ID
+	order.TimeInForce = obj.TimeInForce
+	order.PostOnly = obj.PostOnly
+	order.Account = obj.Account
+	order.STP = obj.STP
 	order.Next = NullIndex
 	order.Prev = NullIndex
+	order.Node = NullIndex
 
-	price := order.Price.Float64()
-	if price <= 0 {
+	if order.Price.Val <= 0 {
 		return errors.New("Order price should be greater than zero")
 	}
-	amount := order.Amount.Float64()
-	if amount <= 0 {
+	if order.Amount.Val <= 0 && order.Funds == nil {
 		return errors.New("Order amount should be greater than zero")
 	}
 	return nil
@@ -124,17 +205,70 @@ func (order *Order) MarshalJSON() ([]byte, error) {
 	if !strings.Contains(price, ".") {
 		price = price + ".0"
 	}
+	// GTC 为默认值，不输出以保持原有 JSON 格式
+	tif := ""
+	if order.TimeInForce.String() != GTC.String() {
+		tif = order.TimeInForce.String()
+	}
+	postOnly := ""
+	if order.PostOnly.String() != PostOnlyNone.String() {
+		postOnly = order.PostOnly.String()
+	}
+	displayAmount := ""
+	if order.DisplayAmount != nil {
+		displayAmount = order.DisplayAmount.String()
+	}
+	stopPrice := ""
+	if order.StopPrice != nil {
+		stopPrice = order.StopPrice.String()
+	}
+	funds := ""
+	if order.Funds != nil {
+		funds = order.Funds.String()
+	}
+	protectionPrice := ""
+	if order.ProtectionPrice != nil {
+		protectionPrice = order.ProtectionPrice.String()
+	}
+	maxSlippage := ""
+	if order.MaxSlippage != nil {
+		maxSlippage = order.MaxSlippage.String()
+	}
+	stp := ""
+	if order.STP.String() != STPCancelNewest.String() {
+		stp = order.STP.String()
+	}
 	return json.Marshal(
 		&struct {
-			Type   string `json:"type"`
-			ID     string `json:"id"`
-			Amount string `json:"amount"`
-			Price  string `json:"price"`
+			Type          string `json:"type"`
+			ID            string `json:"id"`
+			Amount        string `json:"amount"`
+			Price         string `json:"price"`
+			TimeInForce   string `json:"time_in_force,omitempty"`
+			PostOnly      string `json:"post_only,omitempty"`
+			DisplayAmount string `json:"display_amount,omitempty"`
+			StopPrice     string `json:"stop_price,omitempty"`
+			Account       string `json:"account,omitempty"`
+			STP           string `json:"stp,omitempty"`
+			Funds         string `json:"funds,omitempty"`
+
+			ProtectionPrice string `json:"protection_price,omitempty"`
+			MaxSlippage     string `json:"max_slippage,omitempty"`
 		}{
-			Type:   order.Type.String(),
-			ID:     order.ID,
-			Amount: amount,
-			Price:  price,
+			Type:          order.Type.String(),
+			ID:            order.ID,
+			Amount:        amount,
+			Price:         price,
+			TimeInForce:   tif,
+			PostOnly:      postOnly,
+			DisplayAmount: displayAmount,
+			StopPrice:     stopPrice,
+			Account:       order.Account,
+			STP:           stp,
+			Funds:         funds,
+
+			ProtectionPrice: protectionPrice,
+			MaxSlippage:     maxSlippage,
 		},
 	)
 }
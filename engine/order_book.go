@@ -3,23 +3,47 @@ package engine
 import (
 	"encoding/json"
 	"errors"
-	"math"
-	"strconv"
 	"sync"
+	"time"
 
-	"github.com/goovo/binarytree"
 	"github.com/goovo/matching-engine/util"
 )
 
 // OrderBook 订单簿类型
 type OrderBook struct {
-	BuyTree         *binarytree.BinaryTree
-	SellTree        *binarytree.BinaryTree
-	orderLimitRange int
-	orders          map[string]IndexType // orderID -> Arena Index
+	BuyTree         PriceLevels
+	SellTree        PriceLevels
+	orders          orderTable           // 引擎订单 ID -> Arena Index
+	ids             *orderIDs            // 订单 ID 驻留表（字符串 ID -> 引擎订单 ID）
+	retired         []uint64             // 待回收的引擎订单 ID，操作结束时由 releaseIDs 释放
 	Arena           *OrderArena          // 内存管理器
+	Levels          *LevelArena          // 价格档位内存池
 	mutex           *sync.Mutex
-	listener        MatchingListener     // 事件回调接口
+	listener        MatchingListener         // 事件回调接口
+	rejectListener  RejectListener           // 可选的拒单回调（listener 实现时非空）
+	stops           *stopBook                // 条件单簿
+	stopListener    StopListener             // 可选的条件单回调
+	lastPrice       int64                    // 最新成交价（定点数，0 表示尚无成交）
+	stpListener     SelfTradeListener        // 可选的自成交防护回调
+	dustListener    DustListener             // 可选的剩余金额回调
+	cancelListener  CancelListener           // 可选的撤单原因回调
+	policy          MatchingPolicy           // 档位内分配策略，nil 表示价格-时间优先（FIFO）
+	levelOrders     []IndexType              // matchLevel 复用的缓冲区
+	levelAmounts    []int64
+	levelAllocs     []int64
+	groups          *groupBook               // OCO / 括号单订单组
+	state           TradingState             // 交易状态
+	stateListener   StateListener            // 可选的交易状态回调
+	bands           PriceBands               // 价格带（熔断）配置
+	spec            InstrumentSpec           // 交易规则
+	fillHook        func(makerAccount, takerAccount string, side Side, price, amount int64) // 成交记账（二元市场持仓）
+	fees            *feeBook                 // 手续费表与账户滚动成交额，nil 表示不计费
+	feeListener     FeeListener              // 可选的手续费回调
+	clock           func() time.Time         // 时钟（手续费等级按自然日统计成交额、事件时间戳）
+	seq             uint64                   // 最近一个事件的序号
+	bookID          string                   // 订单簿标识（成交 ID 前缀），为空时成交 ID 只含序号
+	eventListener   EventListener            // 可选的事件记录回调
+	execListener    ExecutionListener        // 可选的执行报告回调
 }
 
 // Book 订单簿序列化结构
@@ -35,38 +59,27 @@ type orderinfo struct {
 
 // MarshalJSON 实现 json.Marshaler 接口
 func (ob *OrderBook) MarshalJSON() ([]byte, error) {
-	buys := []orderinfo{}
-	ob.BuyTree.Root.InOrderTraverse(func(i float64) {
-		node := ob.BuyTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InOrderTraverse(func(i float64) {
-			var b orderinfo
-			b.Price = util.NewDecimalFromFloat(i)
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			b.Amount = subNode.Data.(*OrderNode).Volume
-			buys = append(buys, b)
-		})
-	})
-
-	sells := []orderinfo{}
-	ob.SellTree.Root.InOrderTraverse(func(i float64) {
-		node := ob.SellTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InOrderTraverse(func(i float64) {
-			var b orderinfo
-			b.Price = util.NewDecimalFromFloat(i)
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			b.Amount = subNode.Data.(*OrderNode).Volume
-			sells = append(sells, b)
-		})
-	})
-
 	return json.Marshal(
 		&Book{
-			Buys:  buys,
-			Sells: sells,
+			Buys:  depth(ob.BuyTree, true, 0),
+			Sells: depth(ob.SellTree, true, 0),
 		},
 	)
 }
 
+// depth 按价格顺序汇总 tree 的前 limit 个档位（limit 为 0 时不限），价格取档位的精确定点数
+func depth(tree PriceLevels, ascending bool, limit int64) []orderinfo {
+	levels := []orderinfo{}
+	tree.Walk(ascending, func(price int64, node *OrderNode) bool {
+		if int64(len(levels)) >= limit && limit != 0 {
+			return false
+		}
+		levels = append(levels, orderinfo{Price: &util.StandardBigDecimal{Val: price}, Amount: &util.StandardBigDecimal{Val: node.Volume.Val}})
+		return true
+	})
+	return levels
+}
+
 // BookArray 订单簿二维数组结构
 type BookArray struct {
 	Buys  [][]string `json:"buys"`
@@ -79,44 +92,14 @@ func (ob *OrderBook) GetOrders(limit int64) *BookArray {
 	defer ob.mutex.Unlock()
 
 	buys := [][]string{}
-	ob.BuyTree.Root.InReverseOrderTraverse(func(i float64) {
-		node := ob.BuyTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InReverseOrderTraverse(func(i float64) {
-			if int64(len(buys)) >= limit && limit != 0 {
-				return
-			}
-			var b []string
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			if subNode != nil {
-				price := strconv.FormatFloat(i, 'f', -1, 64)
-				b = append(b, price)
-
-				amount := subNode.Data.(*OrderNode).Volume
-				b = append(b, amount.String())
-				buys = append(buys, b)
-			}
-		})
-	})
+	for _, level := range depth(ob.BuyTree, false, limit) {
+		buys = append(buys, []string{level.Price.String(), level.Amount.String()})
+	}
 
 	sells := [][]string{}
-	ob.SellTree.Root.InOrderTraverse(func(i float64) {
-		node := ob.SellTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InOrderTraverse(func(i float64) {
-			if int64(len(sells)) >= limit && limit != 0 {
-				return
-			}
-			var b []string
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			if subNode != nil {
-				price := strconv.FormatFloat(i, 'f', -1, 64)
-				b = append(b, price)
-
-				amount := subNode.Data.(*OrderNode).Volume
-				b = append(b, amount.String())
-				sells = append(sells, b)
-			}
-		})
-	})
+	for _, level := range depth(ob.SellTree, true, limit) {
+		sells = append(sells, []string{level.Price.String(), level.Amount.String()})
+	}
 
 	return &BookArray{
 		Buys:  buys,
@@ -127,177 +110,272 @@ func (ob *OrderBook) GetOrders(limit int64) *BookArray {
 // String 实现 Stringer 接口
 func (ob *OrderBook) String() string {
 	result := ""
-	var orderSideSell []string
-	ob.SellTree.Root.InOrderTraverse(func(i float64) {
-		node := ob.SellTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InOrderTraverse(func(i float64) {
-			res := strconv.FormatFloat(i, 'f', -1, 64) + " -> "
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			if subNode != nil {
-				vol := subNode.Data.(*OrderNode).Volume.Float64()
-				res += strconv.FormatFloat(vol, 'f', -1, 64) 
-				orderSideSell = append(orderSideSell, res)
-			}
-		})
-	})
-	sells := ""
-	for _, o := range orderSideSell {
-		sells = o + "\n" + sells
+	for _, level := range depth(ob.SellTree, false, 0) {
+		result += level.Price.String() + " -> " + level.Amount.String() + "\n"
 	}
-	result = sells + "------------------------------------------\n"
-
-	var orderSideBuy []string
-	ob.BuyTree.Root.InOrderTraverse(func(i float64) {
-		node := ob.BuyTree.Root.SearchSubTree(i)
-		node.Data.(*OrderType).Tree.Root.InOrderTraverse(func(i float64) {
-			res := strconv.FormatFloat(i, 'f', -1, 64) + " -> "
-			subNode := node.Data.(*OrderType).Tree.Root.SearchSubTree(i)
-			if subNode != nil {
-				vol := subNode.Data.(*OrderNode).Volume.Float64()
-				res += strconv.FormatFloat(vol, 'f', -1, 64)
-				orderSideBuy = append(orderSideBuy, res)
-			}
-		})
-	})
-	buys := ""
-	for _, o := range orderSideBuy {
-		buys = o + "\n" + buys
+	result += "------------------------------------------\n"
+	for _, level := range depth(ob.BuyTree, false, 0) {
+		result += level.Price.String() + " -> " + level.Amount.String() + "\n"
 	}
-	result += buys
 	return result
 }
 
 // NewOrderBook 返回新的订单簿
 // listener: 事件回调接口，如果为 nil 则使用 NoOpListener
-func NewOrderBook(listener MatchingListener) *OrderBook {
-	bTree := binarytree.NewBinaryTree()
-	sTree := binarytree.NewBinaryTree()
-	bTree.ToggleSplay(true)
-	sTree.ToggleSplay(true)
-
+// opts: 可选配置，如 WithMatchingPolicy
+func NewOrderBook(listener MatchingListener, opts ...BookOption) *OrderBook {
 	if listener == nil {
 		listener = &NoOpListener{}
 	}
 
-	return &OrderBook{
-		BuyTree:         bTree,
-		SellTree:        sTree,
-		orderLimitRange: 200000000,
-		orders:          make(map[string]IndexType),
+	rejectListener, _ := listener.(RejectListener)
+	stopListener, _ := listener.(StopListener)
+	stpListener, _ := listener.(SelfTradeListener)
+	dustListener, _ := listener.(DustListener)
+	cancelListener, _ := listener.(CancelListener)
+	stateListener, _ := listener.(StateListener)
+	feeListener, _ := listener.(FeeListener)
+	eventListener, _ := listener.(EventListener)
+	execListener, _ := listener.(ExecutionListener)
+
+	levels := NewLevelArena()
+	ob := &OrderBook{
+		BuyTree:         NewPriceTree(levels),
+		SellTree:        NewPriceTree(levels),
+		ids:             newOrderIDs(),
 		Arena:           NewOrderArena(100000), // 默认 10w 容量
+		Levels:          levels,
 		mutex:           &sync.Mutex{},
 		listener:        listener,
+		rejectListener:  rejectListener,
+		stops:           newStopBook(),
+		stopListener:    stopListener,
+		stpListener:     stpListener,
+		dustListener:    dustListener,
+		cancelListener:  cancelListener,
+		groups:          newGroupBook(),
+		state:           StateContinuous,
+		stateListener:   stateListener,
+		spec:            defaultSpec(),
+		feeListener:     feeListener,
+		clock:           time.Now,
+		eventListener:   eventListener,
+		execListener:    execListener,
+	}
+	for _, opt := range opts {
+		opt(ob)
 	}
+	return ob
 }
 
-// addBuyOrder 将买单加入订单簿
-func (ob *OrderBook) addBuyOrder(order Order) {
-	// 分配 Arena 空间
-	idx := ob.Arena.Alloc()
-	storedOrder := ob.Arena.Get(idx)
-	*storedOrder = order // Copy struct
-	storedOrder.Next = NullIndex
-	storedOrder.Prev = NullIndex
-	storedOrder.Node = nil
-
-	orderPrice := order.Price.Float64()
-	startPoint := float64(int(math.Ceil(orderPrice)) / ob.orderLimitRange * ob.orderLimitRange)
-	endPoint := startPoint + float64(ob.orderLimitRange)
-	searchNodePrice := (startPoint + endPoint) / 2
-	
-	node := ob.BuyTree.Root.SearchSubTree(searchNodePrice)
-	if node != nil {
-		subTree := node.Data.(*OrderType)
-		subTreeNode := subTree.Tree.Root.SearchSubTree(orderPrice)
-		if subTreeNode != nil {
-			subTreeNode.Data.(*OrderNode).addOrder(ob.Arena, idx)
-		} else {
-			_, _ = subTree.AddOrderInQueue(ob.Arena, idx)
-		}
+// cancelWithReason 撤销订单（剩余部分），reason 非空时先通知 CancelListener
+func (ob *OrderBook) cancelWithReason(order *Order, reason CancelReason) {
+	if reason != "" && ob.cancelListener != nil {
+		ob.cancelListener.OnOrderCancelReason(order.ID, reason)
+	}
+	ob.listener.OnOrderCancelled(order.ID)
+	ob.emit(EventCancelled, order, string(reason))
+	ob.groups.fire(order)
+}
+
+// onTrade 记录最新成交价并触发成交事件（成交方向为 Maker 方向），设置了手续费表时随后上报手续费
+// 双方的累计成交与按金额下单的 Taker 剩余金额在此更新
+func (ob *OrderBook) onTrade(maker, taker *Order, price, amount int64) {
+	ob.lastPrice = price
+	maker.fill(price, amount)
+	taker.fill(price, amount)
+	if taker.Funds != nil {
+		taker.spendFunds(&util.StandardBigDecimal{Val: price}, &util.StandardBigDecimal{Val: amount})
+	}
+	ob.listener.OnTrade(maker.ID, taker.ID, maker.Type, price, amount)
+	var fees *TradeFees
+	if ob.fees != nil {
+		fees = chargeFees(ob, ob, maker, taker, price, price, amount)
+	}
+	ob.emitTrade(maker, taker, "", price, price, amount, fees)
+	if ob.fillHook != nil {
+		ob.fillHook(maker.Account, taker.Account, maker.Type, price, amount)
+	}
+	if len(ob.groups.byOrder) > 0 {
+		ob.groups.onTrade(maker, amount)
+		ob.groups.onTrade(taker, amount)
+	}
+}
+
+// WithClock 设置订单簿使用的时钟（默认 time.Now）
+func WithClock(clock func() time.Time) BookOption {
+	return func(ob *OrderBook) {
+		ob.clock = clock
+	}
+}
+
+// WithBookID 设置订单簿标识（如交易对名称），成交 ID 形如 "<标识>-<序号>"，
+// 多个订单簿的成交汇总到同一事件流时用于区分成交
+func WithBookID(id string) BookOption {
+	return func(ob *OrderBook) {
+		ob.bookID = id
+	}
+}
+
+// rejectOrder 拒绝订单：优先通知 RejectListener，否则回退为撤单事件
+func (ob *OrderBook) rejectOrder(order *Order, reason RejectReason) {
+	ob.groups.fire(order)
+	if ob.rejectListener != nil {
+		ob.rejectListener.OnOrderRejected(order.ID, reason)
 	} else {
-		orderTypeObj := NewOrderType(order.Type)
-		_, _ = orderTypeObj.AddOrderInQueue(ob.Arena, idx)
-		ob.BuyTree.Insert(searchNodePrice, orderTypeObj)
+		ob.listener.OnOrderCancelled(order.ID)
 	}
-	ob.orders[order.ID] = idx
-	
-	// 触发 Maker 事件
-	ob.listener.OnOrderAccepted(order.ID)
+	ob.emit(EventRejected, order, string(reason))
+}
+
+// addBuyOrder 将买单加入订单簿
+func (ob *OrderBook) addBuyOrder(order Order) {
+	ob.addOrder(ob.BuyTree, order)
 }
 
 // addSellOrder 将卖单加入订单簿
 func (ob *OrderBook) addSellOrder(order Order) {
+	ob.addOrder(ob.SellTree, order)
+}
+
+// addOrder 将订单追加到 tree 中同价档位的队尾，档位不存在时新建
+func (ob *OrderBook) addOrder(tree PriceLevels, order Order) {
 	// 分配 Arena 空间
 	idx := ob.Arena.Alloc()
 	storedOrder := ob.Arena.Get(idx)
 	*storedOrder = order // Copy struct
 	storedOrder.Next = NullIndex
 	storedOrder.Prev = NullIndex
-	storedOrder.Node = nil
-
-	orderPrice := order.Price.Float64()
-	startPoint := float64(int(math.Ceil(orderPrice)) / ob.orderLimitRange * ob.orderLimitRange)
-	endPoint := startPoint + float64(ob.orderLimitRange)
-	searchNodePrice := (startPoint + endPoint) / 2
-	
-	node := ob.SellTree.Root.SearchSubTree(searchNodePrice)
-	if node != nil {
-		subTree := node.Data.(*OrderType)
-		subTreeNode := subTree.Tree.Root.SearchSubTree(orderPrice)
-		if subTreeNode != nil {
-			subTreeNode.Data.(*OrderNode).addOrder(ob.Arena, idx)
-		} else {
-			_, _ = subTree.AddOrderInQueue(ob.Arena, idx)
-		}
-	} else {
-		orderTypeObj := NewOrderType(order.Type)
-		_, _ = orderTypeObj.AddOrderInQueue(ob.Arena, idx)
-		ob.SellTree.Insert(searchNodePrice, orderTypeObj)
+	storedOrder.Node = NullIndex
+	storedOrder.splitIceberg()
+
+	node := tree.Get(order.Price.Val)
+	if node == nil {
+		node = ob.Levels.Get(ob.Levels.Alloc())
+		tree.Insert(order.Price.Val, node)
 	}
-	ob.orders[order.ID] = idx
+	node.addOrder(ob.Arena, idx)
+	storedOrder.EID = ob.engineID(storedOrder)
+	ob.orders.set(storedOrder.EID, idx)
 
 	// 触发 Maker 事件
 	ob.listener.OnOrderAccepted(order.ID)
+	ob.emit(EventAccepted, storedOrder, "")
 }
 
-func (ob *OrderBook) removeBuyNode(key float64) error {
-	node := ob.BuyTree.Root.Remove(key)
-	ob.BuyTree.Root = node
-	return nil
+// lookup 返回字符串订单 ID 对应挂单的 Arena 索引（调用方持有锁）
+func (ob *OrderBook) lookup(id string) (IndexType, bool) {
+	eid, ok := ob.ids.lookup(id)
+	if !ok {
+		return NullIndex, false
+	}
+	return ob.orders.get(eid)
 }
 
-func (ob *OrderBook) removeSellNode(key float64) error {
-	node := ob.SellTree.Root.Remove(key)
-	ob.SellTree.Root = node
-	return nil
+// forget 把订单移出挂单索引，其引擎订单 ID 在本次操作结束后回收（调用方持有锁）
+func (ob *OrderBook) forget(order *Order) {
+	ob.orders.del(order.EID)
+	ob.retire(order.EID)
 }
 
-// removeOrder 从订单簿移除订单
-func (ob *OrderBook) removeOrder(order *Order) error {
-	orderPrice := order.Price.Float64()
-	startPoint := float64(int(math.Ceil(orderPrice)) / ob.orderLimitRange * ob.orderLimitRange)
-	endPoint := startPoint + float64(ob.orderLimitRange)
-	searchNodePrice := (startPoint + endPoint) / 2
-	
-	var node *binarytree.BinaryNode
-	if order.Type == Buy {
-		node = ob.BuyTree.Root.SearchSubTree(searchNodePrice)
-	} else {
-		node = ob.SellTree.Root.SearchSubTree(searchNodePrice)
+// engineID 返回订单的引擎订单 ID：调用方已驻留且与字符串 ID 一致时直接使用（不再求散列），
+// 否则驻留字符串 ID 并写回订单（调用方持有锁）
+func (ob *OrderBook) engineID(order *Order) uint64 {
+	if order.EID != 0 {
+		if name, ok := ob.ids.name(order.EID); ok && name == order.ID {
+			return order.EID
+		}
+	}
+	order.EID = ob.ids.intern(order.ID)
+	ob.retire(order.EID)
+	return order.EID
+}
+
+// rejectDuplicate 订单的字符串 ID 已被挂单或未触发的条件单占用时拒单，返回 true 表示已拒绝（调用方持有锁）
+// 拒单前清除引擎订单 ID，拒单事件与订单组不会关联到占用该 ID 的原订单
+func (ob *OrderBook) rejectDuplicate(order *Order) bool {
+	if !ob.live(ob.engineID(order)) {
+		return false
 	}
-	if node != nil {
-		subTree := node.Data.(*OrderType)
-		subTreeNode := subTree.Tree.Root.SearchSubTree(orderPrice)
-		if subTreeNode != nil {
-			if subTreeNode.Data.(*OrderNode).Count == 0 {
-				n := subTree.Tree.Root.Remove(orderPrice)
-				subTree.Tree.Root = n
-			}
-		} else {
-			return errors.New("no Order found")
+	order.EID = 0
+	ob.rejectOrder(order, RejectDuplicateOrderID)
+	return true
+}
+
+// live 判断引擎订单 ID 是否被挂单或未触发的条件单占用（调用方持有锁）
+func (ob *OrderBook) live(eid uint64) bool {
+	if _, ok := ob.orders.get(eid); ok {
+		return true
+	}
+	_, ok := ob.stops.index[eid]
+	return ok
+}
+
+// retire 登记可能不再被引用的引擎订单 ID（调用方持有锁）
+func (ob *OrderBook) retire(eid uint64) {
+	ob.retired = append(ob.retired, eid)
+}
+
+// releaseIDs 释放已登记且不再被挂单、条件单或订单组引用的引擎订单 ID（调用方持有锁）
+// 只在公开操作结束时调用：操作过程中离开订单簿的订单仍可能被事件、执行报告与订单组引用，
+// 延迟到操作结束可保证同一次操作内引擎订单 ID 不会分配给其他订单
+func (ob *OrderBook) releaseIDs() {
+	for _, eid := range ob.retired {
+		if _, grouped := ob.groups.byOrder[eid]; !grouped && !ob.live(eid) {
+			ob.ids.release(eid)
 		}
-	} else {
+	}
+	ob.retired = ob.retired[:0]
+}
+
+// InternOrderID 返回字符串订单 ID 的引擎订单 ID，供 server 在入口处驻留一次后写入 Order.EID 随订单传入；
+// 订单未在本次驻留后提交到订单簿时，引擎订单 ID 在下一次操作结束时回收
+func (ob *OrderBook) InternOrderID(id string) uint64 {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+
+	eid := ob.ids.intern(id)
+	ob.retire(eid)
+	return eid
+}
+
+// removeOrder 移除并回收订单所在的空价格档位
+func (ob *OrderBook) removeOrder(order *Order) error {
+	tree := ob.BuyTree
+	if order.Type == Sell {
+		tree = ob.SellTree
+	}
+	node := tree.Get(order.Price.Val)
+	if node == nil {
 		return errors.New("no Order found")
 	}
+	if node.Count == 0 {
+		ob.releaseLevel(tree, node)
+	}
 	return nil
 }
+
+// releaseLevel 从 tree 中移除空价格档位并回收到 LevelArena（档位已被移除时不做处理）
+func (ob *OrderBook) releaseLevel(tree PriceLevels, node *OrderNode) {
+	if tree.Get(node.price) == node {
+		tree.Remove(node.price)
+		ob.Levels.Free(node.index)
+	}
+}
+
+// bestPrice 返回对手盘 tree 的最优价（Taker 方向为 side），对手盘为空时返回 nil
+func (ob *OrderBook) bestPrice(side Side, tree PriceLevels) *util.StandardBigDecimal {
+	node := ob.bestNode(tree, side)
+	if node == nil {
+		return nil
+	}
+	return ob.Arena.Get(node.Head).Price
+}
+
+// bestNode 返回单边订单簿的最优价格档位（side 为 Taker 方向，与 bestPrice 一致）
+func (ob *OrderBook) bestNode(tree PriceLevels, side Side) *OrderNode {
+	if side == Sell {
+		return tree.Max()
+	}
+	return tree.Min()
+}
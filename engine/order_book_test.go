@@ -1,10 +1,8 @@
 package engine
 
 import (
-	"math"
 	"testing"
 
-	"github.com/goovo/binarytree"
 	"github.com/goovo/matching-engine/util"
 )
 
@@ -36,20 +34,15 @@ func TestAddOrderInBook(t *testing.T) {
 			ob.addSellOrder(*tt.input)
 		}
 
-		if _, ok := ob.orders[tt.input.ID]; !ok {
+		if _, ok := ob.lookup(tt.input.ID); !ok {
 			t.Fatal("Order should be pushed in orders array")
 		}
 
-		price := tt.input.Price.Float64()
-		startPoint := float64(int(math.Ceil(price)) / ob.orderLimitRange * ob.orderLimitRange)
-		endPoint := startPoint + float64(ob.orderLimitRange)
-		searchNodePrice := (startPoint + endPoint) / 2
-
-		var node *binarytree.BinaryNode
+		var node *OrderNode
 		if tt.input.Type == Buy {
-			node = ob.BuyTree.Root.SearchSubTree(searchNodePrice)
+			node = ob.BuyTree.Get(tt.input.Price.Val)
 		} else {
-			node = ob.SellTree.Root.SearchSubTree(searchNodePrice)
+			node = ob.SellTree.Get(tt.input.Price.Val)
 		}
 
 		if node == nil {
@@ -77,31 +70,18 @@ func TestRemoveOrderNodeFromBook(t *testing.T) {
 		}
 	}
 
-	price := tests[0].input.Price.Float64()
-	startPoint := float64(int(math.Ceil(price)) / ob.orderLimitRange * ob.orderLimitRange)
-	endPoint := startPoint + float64(ob.orderLimitRange)
-	searchNodePrice := (startPoint + endPoint) / 2
-
-	ob.removeBuyNode(searchNodePrice)
-
-	var node *binarytree.BinaryNode
-	node = ob.BuyTree.Root.SearchSubTree(searchNodePrice)
+	price := tests[0].input.Price.Val
+	ob.BuyTree.Remove(price)
 
-	if node != nil {
-		t.Fatal("Buy Mid Price should be get removed from tree")
+	if ob.BuyTree.Get(price) != nil {
+		t.Fatal("Buy price level should be get removed from tree")
 	}
 
-	price = tests[1].input.Price.Float64()
-	startPoint = float64(int(math.Ceil(price)) / ob.orderLimitRange * ob.orderLimitRange)
-	endPoint = startPoint + float64(ob.orderLimitRange)
-	searchNodePrice = (startPoint + endPoint) / 2
-
-	ob.removeSellNode(searchNodePrice)
-
-	node = ob.SellTree.Root.SearchSubTree(searchNodePrice)
+	price = tests[1].input.Price.Val
+	ob.SellTree.Remove(price)
 
-	if node != nil {
-		t.Fatal("Sell Mid Price should be get removed from tree")
+	if ob.SellTree.Get(price) != nil {
+		t.Fatal("Sell price level should be get removed from tree")
 	}
 }
 
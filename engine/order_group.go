@@ -0,0 +1,223 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// GroupLeg 订单组中的一条腿
+type GroupLeg struct {
+	Order Order
+	// 是否按市价单处理（带 StopPrice 时为止损市价单）
+	Market bool
+}
+
+// orderGroup 关联订单组：任一腿成交（或条件单触发）、撤销时撤销其余腿
+// 括号单的 exits 非空：入场单完全成交或结束后，按已成交数量挂出 OCO 出场单
+type orderGroup struct {
+	id    string
+	legs  []uint64 // 各腿的引擎订单 ID
+	done  bool     // 已被某条腿触发，兄弟订单待撤销
+	cause uint64   // 触发的腿
+
+	exits       []GroupLeg
+	entryAmount int64
+	entryFilled int64
+}
+
+// groupBook 订单组索引
+type groupBook struct {
+	byOrder map[uint64]*orderGroup // 引擎订单 ID -> 订单组
+	pending []*orderGroup
+}
+
+func newGroupBook() *groupBook {
+	return &groupBook{byOrder: map[uint64]*orderGroup{}}
+}
+
+// member 返回订单所在的组；引擎订单 ID 只在本订单簿内有效，跨订单簿路由的订单与组不符时返回 nil
+func (gb *groupBook) member(order *Order) *orderGroup {
+	if g, ok := gb.byOrder[order.EID]; ok && g == order.group {
+		return g
+	}
+	return nil
+}
+
+// fire 标记订单所在的组已被触发，兄弟订单在 settleGroups 中撤销
+func (gb *groupBook) fire(order *Order) {
+	if g := gb.member(order); g != nil && !g.done {
+		g.done = true
+		g.cause = order.EID
+		gb.pending = append(gb.pending, g)
+	}
+}
+
+// onTrade 记录订单组成员的成交：OCO 任一成交即触发，括号单入场单完全成交时触发
+func (gb *groupBook) onTrade(order *Order, amount int64) {
+	g := gb.member(order)
+	if g == nil {
+		return
+	}
+	if g.exits == nil {
+		gb.fire(order)
+		return
+	}
+	g.entryFilled += amount
+	if g.entryFilled >= g.entryAmount {
+		gb.fire(order)
+	}
+}
+
+// siblingDone 判断订单所在的组已被其他腿触发（订单应被撤销）
+func (order *Order) siblingDone() bool {
+	return order.group != nil && order.group.done && order.group.cause != order.EID
+}
+
+// hasGroup 判断 g 是否在 groups 中（g 为 nil 时返回 false）
+func hasGroup(groups []*orderGroup, g *orderGroup) bool {
+	for _, v := range groups {
+		if v == g {
+			return g != nil
+		}
+	}
+	return false
+}
+
+// ProcessOCO 原子地提交一组 OCO（one-cancels-other）订单
+// 任一腿成交（或条件单被触发）、被撤销时，同一临界区内撤销其余腿
+func (ob *OrderBook) ProcessOCO(groupID string, legs ...GroupLeg) error {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+
+	if len(legs) < 2 {
+		return errors.New("OCO group requires at least two orders")
+	}
+	if err := ob.checkLegs(legs); err != nil {
+		return err
+	}
+	ob.placeGroup(&orderGroup{id: groupID}, legs)
+	ob.triggerStops()
+	return nil
+}
+
+// ProcessBracket 原子地提交括号单：入场单完全成交后挂出 exits 组成的 OCO 出场单（如止盈限价 + 止损）
+// 入场单部分成交后被撤销时，出场单按已成交数量挂出；未成交即撤销时出场单一并丢弃
+func (ob *OrderBook) ProcessBracket(groupID string, entry GroupLeg, exits ...GroupLeg) error {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+
+	if len(exits) == 0 {
+		return errors.New("bracket requires at least one exit order")
+	}
+	if err := ob.checkLegs(append([]GroupLeg{entry}, exits...)); err != nil {
+		return err
+	}
+	ob.placeGroup(&orderGroup{id: groupID, exits: exits, entryAmount: entry.Order.Amount.Val}, []GroupLeg{entry})
+	ob.triggerStops()
+	return nil
+}
+
+// checkLegs 检查订单组各腿的 ID 唯一且未被占用
+func (ob *OrderBook) checkLegs(legs []GroupLeg) error {
+	seen := map[string]bool{}
+	for _, leg := range legs {
+		id := leg.Order.ID
+		if seen[id] {
+			return errors.New("order ID already exists")
+		}
+		if eid, ok := ob.ids.lookup(id); ok {
+			if _, grouped := ob.groups.byOrder[eid]; grouped || ob.live(eid) {
+				return errors.New("order ID already exists")
+			}
+		}
+		if err := ob.spec.Validate(&leg.Order, leg.Market); err != nil {
+			return err
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// placeGroup 登记订单组并依次提交各腿；组在提交过程中已被触发时，其余腿不再提交
+// ID 已被占用的腿（如括号单挂出出场单时）不登记到组内，提交时按重复 ID 拒单
+func (ob *OrderBook) placeGroup(g *orderGroup, legs []GroupLeg) {
+	orders := make([]Order, len(legs))
+	for i, leg := range legs {
+		orders[i] = leg.Order
+		eid := ob.engineID(&orders[i])
+		if _, grouped := ob.groups.byOrder[eid]; grouped || ob.live(eid) {
+			continue
+		}
+		orders[i].group = g
+		g.legs = append(g.legs, eid)
+		ob.groups.byOrder[eid] = g
+	}
+	for i, leg := range legs {
+		order := orders[i]
+		if g.done && order.group == g {
+			delete(ob.groups.byOrder, order.EID)
+			ob.cancelWithReason(&order, CancelOrderGroup)
+			continue
+		}
+		switch {
+		case order.StopPrice != nil:
+			ob.addStopOrder(order, leg.Market)
+		case leg.Market:
+			ob.processMarket(order)
+		default:
+			ob.process(order)
+		}
+	}
+}
+
+// settleGroups 撤销已触发订单组的兄弟订单，并为括号单挂出出场单（调用方持有锁）
+func (ob *OrderBook) settleGroups() {
+	for len(ob.groups.pending) > 0 {
+		g := ob.groups.pending[0]
+		ob.groups.pending = ob.groups.pending[1:]
+
+		for _, eid := range g.legs {
+			if ob.groups.byOrder[eid] != g {
+				continue
+			}
+			delete(ob.groups.byOrder, eid)
+			ob.retire(eid)
+			if eid != g.cause {
+				ob.cancelLeg(eid)
+			}
+		}
+
+		if g.exits != nil && g.entryFilled > 0 {
+			legs := make([]GroupLeg, len(g.exits))
+			for i, exit := range g.exits {
+				legs[i] = exit
+				legs[i].Order.Amount = &util.StandardBigDecimal{Val: g.entryFilled}
+			}
+			ob.placeGroup(&orderGroup{id: g.id}, legs)
+		}
+	}
+}
+
+// cancelLeg 撤销订单组中仍在订单簿或条件单簿中的腿
+func (ob *OrderBook) cancelLeg(eid uint64) {
+	if idx, ok := ob.orders.get(eid); ok {
+		order := ob.Arena.Get(idx)
+		ob.removeIndex(idx)
+		ob.cancelWithReason(order, CancelOrderGroup)
+		return
+	}
+	if stop := ob.stops.cancel(eid); stop != nil {
+		ob.cancelWithReason(stop, CancelOrderGroup)
+	}
+}
+
+// cancelMaker 撮合过程中撤销档位内的 Maker（冰山单整体撤销，不再刷新）
+func (ob *OrderBook) cancelMaker(node *OrderNode, idx IndexType, reason CancelReason) {
+	order := ob.Arena.Get(idx)
+	ob.forget(order)
+	node.removeOrder(ob.Arena, idx)
+	ob.cancelWithReason(order, reason)
+}
@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"testing"
+)
+
+func newOCOBook() (*OrderBook, *stopListener) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("b0", Buy, DecimalBig("5.0"), DecimalBig("89.0")))
+	ob.Process(*NewOrder("s0", Sell, DecimalBig("5.0"), DecimalBig("120.0")))
+	return ob, listener
+}
+
+// 止盈卖单 110 + 止损卖单 90
+func ocoLegs() []GroupLeg {
+	return []GroupLeg{
+		{Order: *NewOrder("tp", Sell, DecimalBig("1.0"), DecimalBig("110.0"))},
+		{Order: newStop("sl", Sell, "1.0", "0.0", "90.0"), Market: true},
+	}
+}
+
+func hasID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOCOFillCancelsSibling(t *testing.T) {
+	ob, listener := newOCOBook()
+	if err := ob.ProcessOCO("g", ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("0.5"), DecimalBig("110.0")))
+
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "tp" {
+		t.Fatalf("take-profit should fill (have: %+v)", listener.Trades)
+	}
+	if !hasID(listener.Cancelled, "sl") || len(ob.stops.index) != 0 {
+		t.Fatal("stop leg should be cancelled on fill")
+	}
+	if _, ok := ob.lookup("tp"); !ok {
+		t.Fatal("partially filled leg should keep resting")
+	}
+
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("0.5"), DecimalBig("110.0")))
+	if len(listener.Trades) != 2 || listener.Trades[1].MakerID != "tp" {
+		t.Fatalf("remaining leg should still be matchable (have: %+v)", listener.Trades)
+	}
+}
+
+func TestOCOStopTriggerCancelsSibling(t *testing.T) {
+	ob, listener := newOCOBook()
+	if err := ob.ProcessOCO("g", ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("89.0")))
+
+	if len(listener.StopTriggered) != 1 || !hasID(listener.Cancelled, "tp") {
+		t.Fatal("triggered stop should cancel take-profit")
+	}
+	if _, ok := ob.lookup("tp"); ok {
+		t.Fatal("take-profit should be removed from book")
+	}
+}
+
+func TestOCOCancelCancelsSibling(t *testing.T) {
+	ob, listener := newOCOBook()
+	if err := ob.ProcessOCO("g", ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	if ob.CancelOrder("tp") == nil {
+		t.Fatal("leg should be cancellable")
+	}
+	if !hasID(listener.Cancelled, "sl") || len(ob.stops.index) != 0 {
+		t.Fatal("cancel should cascade to sibling")
+	}
+}
+
+func TestOCONoDoubleFillInOneSweep(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	err := ob.ProcessOCO("g",
+		GroupLeg{Order: *NewOrder("a", Sell, DecimalBig("1.0"), DecimalBig("101.0"))},
+		GroupLeg{Order: *NewOrder("b", Sell, DecimalBig("1.0"), DecimalBig("102.0"))},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("t", Buy, DecimalBig("2.0"), DecimalBig("102.0")))
+
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "a" {
+		t.Fatalf("only one leg should fill (have: %+v)", listener.Trades)
+	}
+	if !hasID(listener.Cancelled, "b") {
+		t.Fatal("sibling hit in the same sweep should be cancelled")
+	}
+	if rest := ob.Arena.Get(indexOf(ob, "t")); rest.Amount.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("taker remainder should rest (have: %s)", rest.Amount)
+	}
+}
+
+func TestBracketSpawnsExits(t *testing.T) {
+	ob, listener := newOCOBook()
+	entry := GroupLeg{Order: *NewOrder("entry", Buy, DecimalBig("2.0"), DecimalBig("100.0"))}
+	if err := ob.ProcessBracket("g", entry, ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ob.lookup("tp"); ok {
+		t.Fatal("exits should wait for entry fill")
+	}
+
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	if _, ok := ob.lookup("tp"); ok {
+		t.Fatal("exits should wait for full entry fill")
+	}
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	tp, ok := ob.lookup("tp")
+	if !ok || ob.Arena.Get(tp).Amount.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatal("take-profit should be placed for the filled amount")
+	}
+	if stop, ok := ob.stop("sl"); !ok || stop.order.Amount.Cmp(DecimalBig("2.0")) != 0 {
+		t.Fatal("stop-loss should be placed for the filled amount")
+	}
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("2.0"), DecimalBig("110.0")))
+	if !hasID(listener.Cancelled, "sl") {
+		t.Fatal("exit pair should behave as OCO")
+	}
+}
+
+func TestBracketEntryCancelled(t *testing.T) {
+	ob, _ := newOCOBook()
+	entry := GroupLeg{Order: *NewOrder("entry", Buy, DecimalBig("2.0"), DecimalBig("100.0"))}
+	if err := ob.ProcessBracket("g", entry, ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("0.5"), DecimalBig("100.0")))
+	ob.CancelOrder("entry")
+
+	tp, ok := ob.lookup("tp")
+	if !ok || ob.Arena.Get(tp).Amount.Cmp(DecimalBig("0.5")) != 0 {
+		t.Fatal("exits should be sized to the partial fill")
+	}
+
+	ob, _ = newOCOBook()
+	if err := ob.ProcessBracket("g", entry, ocoLegs()...); err != nil {
+		t.Fatal(err)
+	}
+	ob.CancelOrder("entry")
+	if _, ok := ob.lookup("tp"); ok || len(ob.stops.index) != 0 || len(ob.groups.byOrder) != 0 {
+		t.Fatal("unfilled bracket should be dropped")
+	}
+}
+
+func TestOrderGroupErrors(t *testing.T) {
+	ob, _ := newOCOBook()
+	if err := ob.ProcessOCO("g", ocoLegs()[0]); err == nil {
+		t.Fatal("OCO with a single leg should fail")
+	}
+	legs := ocoLegs()
+	legs[1].Order.ID = "b0"
+	if err := ob.ProcessOCO("g", legs...); err == nil {
+		t.Fatal("duplicate order ID should fail")
+	}
+	if len(ob.groups.byOrder) != 0 {
+		t.Fatal("failed group should not be registered")
+	}
+}
+
+func TestFOKSkipsOCOSiblings(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	// 同组两条卖单腿：成交其中一条后另一条被撤销，只能提供 1 份流动性
+	if err := ob.ProcessOCO("g",
+		GroupLeg{Order: *NewOrder("a", Sell, DecimalBig("1.0"), DecimalBig("100.0"))},
+		GroupLeg{Order: *NewOrder("b", Sell, DecimalBig("1.0"), DecimalBig("100.0"))},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	fok := *NewOrder("fok", Buy, DecimalBig("2.0"), DecimalBig("100.0"))
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	if len(listener.Trades) != 0 || !hasID(listener.Cancelled, "fok") {
+		t.Fatalf("FOK should be killed without partial fills (have: %+v)", listener.Trades)
+	}
+	if _, ok := ob.lookup("b"); !ok {
+		t.Fatal("OCO legs should keep resting")
+	}
+
+	fok = *NewOrder("fok2", Buy, DecimalBig("1.0"), DecimalBig("100.0"))
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "a" || !hasID(listener.Cancelled, "b") {
+		t.Fatalf("FOK within one leg should fill and cancel the sibling (have: %+v)", listener.Trades)
+	}
+}
@@ -0,0 +1,170 @@
+package engine
+
+// orderIDs 订单 ID 驻留表：把客户端字符串订单 ID 映射为稠密的 uint64 引擎 ID（从 1 开始，释放后复用）
+// 字符串查找使用开放寻址（线性探测）散列表，散列值按引擎 ID 保存，
+// 释放与扩容只比较整数，撮合中订单成交、撤单时不再对字符串求散列
+// 引擎 ID 只用于订单簿内部的索引（挂单、条件单与订单组）；Order.ID 与各监听器回调仍使用字符串 ID，
+// 未携带引擎 ID 的订单（不经 server 直接调用引擎）进入订单簿时仍需对字符串 ID 求一次散列
+type orderIDs struct {
+	slots  []uint64 // 开放寻址散列表，存放引擎 ID（0 表示空槽），长度为 2 的幂
+	names  []string // 下标为引擎 ID，names[0] 不使用
+	hashes []uint64 // 下标为引擎 ID，字符串 ID 的散列值
+	live   []bool   // 下标为引擎 ID，是否已驻留
+	free   []uint64 // 已释放、可复用的引擎 ID
+	count  int
+}
+
+func newOrderIDs() *orderIDs {
+	return &orderIDs{
+		slots:  make([]uint64, 64),
+		names:  []string{""},
+		hashes: []uint64{0},
+		live:   []bool{false},
+	}
+}
+
+// hashID 字符串 ID 的 FNV-1a 散列
+func hashID(id string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(id); i++ {
+		h ^= uint64(id[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// lookup 返回字符串 ID 对应的引擎 ID，未驻留时返回 false
+func (t *orderIDs) lookup(id string) (uint64, bool) {
+	return t.find(id, hashID(id))
+}
+
+// find 按散列值 h 查找字符串 ID 对应的引擎 ID
+func (t *orderIDs) find(id string, h uint64) (uint64, bool) {
+	mask := uint64(len(t.slots) - 1)
+	for i := h & mask; ; i = (i + 1) & mask {
+		eid := t.slots[i]
+		if eid == 0 {
+			return 0, false
+		}
+		if t.hashes[eid] == h && t.names[eid] == id {
+			return eid, true
+		}
+	}
+}
+
+// intern 返回字符串 ID 的引擎 ID，未驻留时分配新的引擎 ID（字符串只求一次散列）
+func (t *orderIDs) intern(id string) uint64 {
+	h := hashID(id)
+	if eid, ok := t.find(id, h); ok {
+		return eid
+	}
+	if (t.count+1)*4 > len(t.slots)*3 {
+		// 负载因子超过 3/4 时扩容
+		t.grow()
+	}
+	var eid uint64
+	if n := len(t.free); n > 0 {
+		eid = t.free[n-1]
+		t.free = t.free[:n-1]
+		t.names[eid], t.hashes[eid], t.live[eid] = id, h, true
+	} else {
+		eid = uint64(len(t.names))
+		t.names = append(t.names, id)
+		t.hashes = append(t.hashes, h)
+		t.live = append(t.live, true)
+	}
+	t.insert(eid)
+	t.count++
+	return eid
+}
+
+// name 返回引擎 ID 对应的字符串 ID，未驻留时返回 false
+func (t *orderIDs) name(eid uint64) (string, bool) {
+	if eid >= uint64(len(t.live)) || !t.live[eid] {
+		return "", false
+	}
+	return t.names[eid], true
+}
+
+// release 释放引擎 ID，留待之后驻留的订单复用；未驻留时不做任何事
+func (t *orderIDs) release(eid uint64) {
+	if _, ok := t.name(eid); !ok {
+		return
+	}
+	mask := uint64(len(t.slots) - 1)
+	i := t.hashes[eid] & mask
+	for t.slots[i] != eid {
+		i = (i + 1) & mask
+	}
+	// 反向移位删除：把探测链上后续的 ID 前移填补空槽，不留墓碑
+	for j := (i + 1) & mask; t.slots[j] != 0; j = (j + 1) & mask {
+		home := t.hashes[t.slots[j]] & mask
+		if i <= j && (home <= i || home > j) || i > j && home <= i && home > j {
+			t.slots[i] = t.slots[j]
+			i = j
+		}
+	}
+	t.slots[i] = 0
+	t.names[eid], t.live[eid] = "", false
+	t.free = append(t.free, eid)
+	t.count--
+}
+
+// insert 把引擎 ID 放入散列表的空槽
+func (t *orderIDs) insert(eid uint64) {
+	mask := uint64(len(t.slots) - 1)
+	i := t.hashes[eid] & mask
+	for t.slots[i] != 0 {
+		i = (i + 1) & mask
+	}
+	t.slots[i] = eid
+}
+
+// grow 散列表容量翻倍并重新放置全部引擎 ID
+func (t *orderIDs) grow() {
+	old := t.slots
+	t.slots = make([]uint64, len(old)*2)
+	for _, eid := range old {
+		if eid != 0 {
+			t.insert(eid)
+		}
+	}
+}
+
+// orderTable 挂单索引：以引擎 ID 为下标直接存取挂单的 Arena 索引（NullIndex 表示不在订单簿中）
+type orderTable struct {
+	index []IndexType
+	count int
+}
+
+// get 返回引擎 ID 对应挂单的 Arena 索引
+func (t *orderTable) get(eid uint64) (IndexType, bool) {
+	if eid >= uint64(len(t.index)) || t.index[eid] == NullIndex {
+		return NullIndex, false
+	}
+	return t.index[eid], true
+}
+
+// set 记录引擎 ID 对应挂单的 Arena 索引，下标超出时扩容
+func (t *orderTable) set(eid uint64, idx IndexType) {
+	for eid >= uint64(len(t.index)) {
+		t.index = append(t.index, NullIndex)
+	}
+	if t.index[eid] == NullIndex {
+		t.count++
+	}
+	t.index[eid] = idx
+}
+
+// del 移除引擎 ID 对应的挂单
+func (t *orderTable) del(eid uint64) {
+	if eid < uint64(len(t.index)) && t.index[eid] != NullIndex {
+		t.index[eid] = NullIndex
+		t.count--
+	}
+}
+
+// size 返回挂单数
+func (t *orderTable) size() int {
+	return t.count
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// indexOf 返回挂单的 Arena 索引，订单不在订单簿中时返回 NullIndex
+func indexOf(ob *OrderBook, id string) IndexType {
+	idx, _ := ob.lookup(id)
+	return idx
+}
+
+func TestOrderIDs(t *testing.T) {
+	ids := newOrderIDs()
+	a, b := ids.intern("a"), ids.intern("b")
+	if a == 0 || b == 0 || a == b || ids.intern("a") != a {
+		t.Fatalf("interned IDs should be dense and stable (have: %d, %d)", a, b)
+	}
+	if name, ok := ids.name(b); !ok || name != "b" {
+		t.Fatalf("engine ID should resolve to its string ID (have: %q)", name)
+	}
+
+	ids.release(a)
+	if _, ok := ids.lookup("a"); ok {
+		t.Fatal("released ID should not be found")
+	}
+	if _, ok := ids.name(a); ok {
+		t.Fatal("released engine ID should not resolve")
+	}
+	if c := ids.intern(""); c != a {
+		t.Fatalf("released engine ID should be reused (have: %d, want: %d)", c, a)
+	}
+	if eid, ok := ids.lookup(""); !ok || eid != a {
+		t.Fatal("empty string ID should be interned like any other")
+	}
+}
+
+func TestOrderIDsRandom(t *testing.T) {
+	ids := newOrderIDs()
+	expected := map[string]uint64{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		// 驻留与释放交替，覆盖扩容与反向移位删除
+		id := fmt.Sprintf("o-%d", r.Intn(3000))
+		if eid, ok := expected[id]; ok && r.Intn(2) == 0 {
+			ids.release(eid)
+			delete(expected, id)
+			continue
+		}
+		eid := ids.intern(id)
+		if want, ok := expected[id]; ok && eid != want {
+			t.Fatalf("%s should keep engine ID %d (have: %d)", id, want, eid)
+		}
+		expected[id] = eid
+	}
+
+	if ids.count != len(expected) || len(ids.names) > 3001 {
+		t.Fatalf("unexpected table size (have: %d IDs, %d engine IDs)", ids.count, len(ids.names)-1)
+	}
+	for i := 0; i < 3000; i++ {
+		id := fmt.Sprintf("o-%d", i)
+		eid, ok := ids.lookup(id)
+		if want, interned := expected[id]; ok != interned || ok && eid != want {
+			t.Fatalf("%s should resolve to %d (have: %d, %v)", id, want, eid, ok)
+		}
+	}
+}
+
+func TestOrderTableReusesEngineIDs(t *testing.T) {
+	ob := NewOrderBook(nil)
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("o-%d", i)
+		ob.Process(*NewOrder(id, Buy, DecimalBig("1.0"), DecimalBig("90.0")))
+		ob.CancelOrder(id)
+	}
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("90.0")))
+	if ob.orders.size() != 1 || len(ob.orders.index) > 2 || ob.ids.count != 1 {
+		t.Fatalf("cancelled orders should release their engine IDs (have: %d slots)", len(ob.orders.index))
+	}
+	if order := ob.Arena.Get(indexOf(ob, "b1")); order.ID != "b1" {
+		t.Fatalf("order should be found by its string ID (have: %s)", order.ID)
+	}
+}
+
+func TestDuplicateOrderIDRejected(t *testing.T) {
+	listener := &rejectListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("x", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("x", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+	if listener.Rejected["x"] != RejectDuplicateOrderID || ob.SellTree.Len() != 1 {
+		t.Fatalf("order ID already resting should be rejected (have: %q)", listener.Rejected["x"])
+	}
+
+	// 第一笔 x 成交后引擎订单 ID 交给 y 复用，之后同名的 x 成交不能影响 y
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("y", Buy, DecimalBig("1.0"), DecimalBig("99.0")))
+	ob.Process(*NewOrder("x", Sell, DecimalBig("1.0"), DecimalBig("102.0")))
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("102.0")))
+	if ob.CancelOrder("y") == nil || ob.BuyTree.Len() != 0 || ob.SellTree.Len() != 0 {
+		t.Fatal("y should be cancelled after a reused order ID is filled")
+	}
+
+	ob.Process(newStop("s1", Buy, "1.0", "110.0", "105.0"))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("110.0")))
+	if listener.Rejected["s1"] != RejectDuplicateOrderID || ob.SellTree.Len() != 0 {
+		t.Fatal("order ID held by a pending stop order should be rejected")
+	}
+
+	ob.Process(*NewOrder("r", Buy, DecimalBig("1.0"), DecimalBig("50.0")))
+	ob.ProcessMarket(*NewOrder("r", Sell, DecimalBig("1.0"), DecimalBig("0.0")))
+	if listener.Rejected["r"] != RejectDuplicateOrderID || ob.BuyTree.Len() != 1 {
+		t.Fatal("market order reusing a resting order ID should be rejected")
+	}
+}
+
+func TestInternOrderID(t *testing.T) {
+	ob := NewOrderBook(nil)
+	order := NewOrder("a", Buy, DecimalBig("1.0"), DecimalBig("90.0"))
+	order.EID = ob.InternOrderID("a")
+	ob.Process(*order)
+	if idx, ok := ob.orders.get(order.EID); !ok || ob.Arena.Get(idx).ID != "a" {
+		t.Fatal("order should rest under the engine ID interned at the edge")
+	}
+
+	// 与字符串 ID 不符的引擎 ID 被忽略，订单簿重新驻留
+	stale := NewOrder("b", Buy, DecimalBig("1.0"), DecimalBig("91.0"))
+	stale.EID = order.EID
+	ob.Process(*stale)
+	if ob.Arena.Get(indexOf(ob, "a")).ID != "a" || ob.Arena.Get(indexOf(ob, "b")).ID != "b" {
+		t.Fatal("stale engine ID should not replace the resting order")
+	}
+
+	// 驻留后未提交的 ID 在下一次操作结束时回收
+	ob.InternOrderID("unused")
+	ob.CancelOrder("b")
+	if _, ok := ob.ids.lookup("unused"); ok || ob.ids.count != 1 {
+		t.Fatalf("unused engine IDs should be released (have: %d interned)", ob.ids.count)
+	}
+}
@@ -2,56 +2,29 @@ package engine
 
 import (
 	"encoding/json"
-	"sync"
 
 	"github.com/goovo/matching-engine/util"
 )
 
-// OrderNode 价格节点，包含订单队列与聚合成交量
+// OrderNode 价格节点，包含订单队列与聚合成交量，由 LevelArena 分配
 type OrderNode struct {
-	Head   IndexType                `json:"-"`
-	Tail   IndexType                `json:"-"`
-	Count  int                      `json:"count"`
-	Volume *util.StandardBigDecimal `json:"volume"`
-}
-
-var orderNodePool = sync.Pool{
-	New: func() interface{} {
-		return &OrderNode{}
-	},
-}
-
-// GetOrderNode 从池中获取 OrderNode 对象
-func GetOrderNode() *OrderNode {
-	return orderNodePool.Get().(*OrderNode)
-}
-
-// Release 将 OrderNode 对象放回池中
-func (on *OrderNode) Release() {
-	// StandardBigDecimal 无需 Release
-	on.Head = NullIndex
-	on.Tail = NullIndex
-	on.Count = 0
-	on.Volume = nil
-	orderNodePool.Put(on)
-}
-
-// NewOrderNode 返回新的 OrderNode 结构体
-func NewOrderNode() *OrderNode {
-	on := GetOrderNode()
-	on.Head = NullIndex
-	on.Tail = NullIndex
-	on.Count = 0
-	// 确保 Volume 也是从池中获取 (现在不需要了，直接创建)
-	on.Volume = util.NewDecimalFromFloat(0.0)
-	return on
+	Head   IndexType               `json:"-"`
+	Tail   IndexType               `json:"-"`
+	Count  int                     `json:"count"`
+	Volume util.StandardBigDecimal `json:"volume"`
+
+	// 档位在 LevelArena 中的索引、价格（定点数）与所在 PriceTree 的 AVL 链接
+	index       IndexType
+	price       int64
+	left, right IndexType
+	height      int8
 }
 
 // addOrder 将订单加入节点（链表尾部追加）
 func (on *OrderNode) addOrder(arena *OrderArena, orderIdx IndexType) {
 	order := arena.Get(orderIdx)
-	order.Node = on
-	
+	order.Node = on.index
+
 	// 使用原地更新
 	on.Volume.AddMut(order.Amount)
 
@@ -79,6 +52,14 @@ func (on *OrderNode) updateVolume(value *util.StandardBigDecimal) {
 
 // removeOrder 从节点中移除指定订单（O(1) 操作）
 func (on *OrderNode) removeOrder(arena *OrderArena, orderIdx IndexType) {
+	on.unlinkOrder(arena, orderIdx)
+
+	// 回收 Index 到 Arena
+	arena.Free(orderIdx)
+}
+
+// unlinkOrder 将订单从节点链表中摘除，但不释放 Arena 槽位
+func (on *OrderNode) unlinkOrder(arena *OrderArena, orderIdx IndexType) {
 	order := arena.Get(orderIdx)
 	
 	// 直接减去订单金额，避免创建负数临时对象
@@ -102,11 +83,8 @@ func (on *OrderNode) removeOrder(arena *OrderArena, orderIdx IndexType) {
 
 	order.Prev = NullIndex
 	order.Next = NullIndex
-	order.Node = nil
+	order.Node = NullIndex
 	on.Count--
-	
-	// 回收 Index 到 Arena
-	arena.Free(orderIdx)
 }
 
 // ToJSONWithArena 辅助序列化方法
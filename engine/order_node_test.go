@@ -4,8 +4,34 @@ import (
 	"testing"
 )
 
-func TestNewOrderNode(t *testing.T) {
-	t.Log(NewOrderNode())
+func TestLevelArena(t *testing.T) {
+	levels := NewLevelArena()
+	var indexes []IndexType
+	for i := 0; i < LevelPageSize+10; i++ {
+		idx := levels.Alloc()
+		on := levels.Get(idx)
+		if on.index != idx || on.Head != NullIndex || on.Tail != NullIndex || on.Count != 0 || on.Volume.Val != 0 {
+			t.Fatalf("level %d should be empty (have: %+v)", idx, on)
+		}
+		on.Count, on.Volume.Val = 1, 1
+		indexes = append(indexes, idx)
+	}
+	if len(levels.pages) != 2 {
+		t.Fatalf("levels should span two pages (have: %d)", len(levels.pages))
+	}
+
+	// 回收的档位按后进先出复用，且重新分配时已清空
+	levels.Free(indexes[3])
+	levels.Free(indexes[LevelPageSize+1])
+	if idx := levels.Alloc(); idx != indexes[LevelPageSize+1] || levels.Get(idx).Count != 0 {
+		t.Fatalf("freed level should be reused (have: %d)", idx)
+	}
+	if idx := levels.Alloc(); idx != indexes[3] || levels.Get(idx).Volume.Val != 0 {
+		t.Fatalf("freed level should be reused (have: %d)", idx)
+	}
+	if idx := levels.Alloc(); idx != IndexType(LevelPageSize+10) {
+		t.Fatalf("new level should be appended (have: %d)", idx)
+	}
 }
 
 func TestAddOrderInNode(t *testing.T) {
@@ -17,7 +43,8 @@ func TestAddOrderInNode(t *testing.T) {
 		{NewOrder("b3", Buy, DecimalBig("11.0"), DecimalBig("7000.0"))},
 		{NewOrder("b4", Buy, DecimalBig("1.0"), DecimalBig("7000.0"))},
 	}
-	on := NewOrderNode()
+	levels := NewLevelArena()
+	on := levels.Get(levels.Alloc())
 	arena := NewOrderArena(100)
 	volume := DecimalBig("0.0")
 	for _, tt := range tests {
@@ -49,7 +76,8 @@ func TestRemoveOrderFromNode(t *testing.T) {
 		{NewOrder("b3", Buy, DecimalBig("11.0"), DecimalBig("7000.0"))},
 		{NewOrder("b4", Buy, DecimalBig("1.0"), DecimalBig("7000.0"))},
 	}
-	on := NewOrderNode()
+	levels := NewLevelArena()
+	on := levels.Get(levels.Alloc())
 	arena := NewOrderArena(100)
 	volume := DecimalBig("0.0")
 	var firstIdx IndexType
@@ -89,7 +117,8 @@ func TestUpdateVolume(t *testing.T) {
 		{NewOrder("b3", Buy, DecimalBig("11.0"), DecimalBig("7000.0"))},
 		{NewOrder("b4", Buy, DecimalBig("1.0"), DecimalBig("7000.0"))},
 	}
-	on := NewOrderNode()
+	levels := NewLevelArena()
+	on := levels.Get(levels.Alloc())
 	volume := DecimalBig("0.0")
 	for _, tt := range tests {
 		on.updateVolume(tt.input.Amount)
@@ -0,0 +1,134 @@
+package engine
+
+import "github.com/goovo/matching-engine/util"
+
+// OrderInfo 挂单或未触发条件单的状态快照
+type OrderInfo struct {
+	ID        string
+	Account   string
+	Side      Side
+	Price     *util.StandardBigDecimal
+	StopPrice *util.StandardBigDecimal // 未触发条件单的触发价，挂单为 nil
+	Quantity  *util.StandardBigDecimal // 委托数量（修改数量后为修改后的委托数量）
+	Remaining *util.StandardBigDecimal // 剩余数量（冰山单含隐藏储备）
+	Filled    *util.StandardBigDecimal // 累计成交数量
+	Status    OrderStatus
+	// 在价格档位队列中的位置（0 表示队首）及排在前面的订单显示数量之和，未触发条件单为 -1 与 nil
+	QueuePosition int
+	VolumeAhead   *util.StandardBigDecimal
+}
+
+// OpenOrdersFilter ListOpenOrders 的过滤条件，零值字段不参与过滤
+type OpenOrdersFilter struct {
+	// 账户（所有者）ID
+	Account string
+	// 订单方向，为空时返回双边
+	Side Side
+}
+
+// GetOrder 返回挂单（或未触发条件单）的状态，订单不在订单簿中时返回 nil
+func (ob *OrderBook) GetOrder(id string) *OrderInfo {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+
+	if idx, ok := ob.lookup(id); ok {
+		return ob.restingInfo(idx)
+	}
+	if stop, ok := ob.stop(id); ok {
+		return stopInfo(&stop.order)
+	}
+	return nil
+}
+
+// ListOpenOrders 按条件返回挂单与未触发条件单：挂单按价格优先、时间优先排列（买盘由高到低、卖盘由低到高），
+// 条件单按触发顺序排在其后
+func (ob *OrderBook) ListOpenOrders(filter OpenOrdersFilter) []OrderInfo {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+
+	orders := []OrderInfo{}
+	for _, side := range []Side{Buy, Sell} {
+		if filter.Side != "" && filter.Side != side {
+			continue
+		}
+		tree := ob.BuyTree
+		if side == Sell {
+			tree = ob.SellTree
+		}
+		tree.Walk(side == Sell, func(price int64, node *OrderNode) bool {
+			// 遍历档位队列时同时累计队列位置，每个档位只遍历一次
+			var position int
+			var ahead int64
+			for idx := node.Head; idx != NullIndex; idx = ob.Arena.Get(idx).Next {
+				order := ob.Arena.Get(idx)
+				if filter.Account == "" || order.Account == filter.Account {
+					orders = append(orders, *queuedInfo(order, position, ahead))
+				}
+				position++
+				ahead += order.Amount.Val
+			}
+			return true
+		})
+
+		queue := ob.stops.buys
+		if side == Sell {
+			queue = ob.stops.sells
+		}
+		for _, stop := range queue {
+			if filter.Account == "" || stop.order.Account == filter.Account {
+				orders = append(orders, *stopInfo(&stop.order))
+			}
+		}
+	}
+	return orders
+}
+
+// restingInfo 返回 Arena 中挂单的状态快照（调用方持有锁）
+func (ob *OrderBook) restingInfo(idx IndexType) *OrderInfo {
+	order := ob.Arena.Get(idx)
+	var position int
+	var ahead int64
+	for i := ob.Levels.Get(order.Node).Head; i != idx; i = ob.Arena.Get(i).Next {
+		position++
+		ahead += ob.Arena.Get(i).Amount.Val
+	}
+	return queuedInfo(order, position, ahead)
+}
+
+// queuedInfo 返回挂单的状态快照，position 与 ahead 为其在档位队列中的位置及排在前面的显示数量之和
+func queuedInfo(order *Order, position int, ahead int64) *OrderInfo {
+	info := orderInfo(order)
+	info.Remaining = order.remaining()
+	info.QueuePosition = position
+	info.VolumeAhead = &util.StandardBigDecimal{Val: ahead}
+	return info
+}
+
+// stopInfo 返回未触发条件单的状态快照
+func stopInfo(order *Order) *OrderInfo {
+	info := orderInfo(order)
+	info.StopPrice = order.StopPrice.Clone()
+	info.Remaining = &util.StandardBigDecimal{}
+	if order.Amount != nil {
+		info.Remaining = order.Amount.Clone()
+	}
+	info.QueuePosition = -1
+	return info
+}
+
+// orderInfo 返回订单的公共状态字段
+func orderInfo(order *Order) *OrderInfo {
+	report := order.report(ExecNew, "")
+	info := &OrderInfo{
+		ID:       order.ID,
+		Account:  order.Account,
+		Side:     order.Type,
+		Quantity: &util.StandardBigDecimal{Val: report.Quantity},
+		Filled:   &util.StandardBigDecimal{Val: order.filled},
+		Status:   report.Status,
+	}
+	if order.Price != nil {
+		info.Price = order.Price.Clone()
+	}
+	return info
+}
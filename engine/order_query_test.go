@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestGetOrder(t *testing.T) {
+	ob := NewOrderBook(nil)
+	ob.Process(newBinaryOrder("s1", "a", Sell, "1.0", "100.0"))
+	ob.Process(newBinaryOrder("s2", "b", Sell, "2.0", "100.0"))
+	ob.Process(newBinaryOrder("s3", "a", Sell, "3.0", "100.0"))
+	ob.Process(newBinaryOrder("b1", "c", Buy, "2.0", "100.0"))
+
+	info := ob.GetOrder("s2")
+	if info == nil {
+		t.Fatal("s2 should be resting")
+	}
+	if info.Side != Sell || info.Price.Cmp(DecimalBig("100.0")) != 0 || info.Quantity.Cmp(DecimalBig("2.0")) != 0 ||
+		info.Remaining.Cmp(DecimalBig("1.0")) != 0 || info.Filled.Cmp(DecimalBig("1.0")) != 0 || info.Status != StatusPartiallyFilled {
+		t.Fatalf("unexpected order info %+v", info)
+	}
+	if info.QueuePosition != 0 || info.VolumeAhead.Val != 0 {
+		t.Fatalf("s2 should be at the head of the queue (have: %d)", info.QueuePosition)
+	}
+
+	info = ob.GetOrder("s3")
+	if info.QueuePosition != 1 || info.VolumeAhead.Cmp(DecimalBig("1.0")) != 0 || info.Status != StatusNew {
+		t.Fatalf("unexpected queue position %+v", info)
+	}
+	if ob.GetOrder("s1") != nil || ob.GetOrder("b1") != nil {
+		t.Fatal("filled orders should not be found")
+	}
+
+	stop := newBinaryOrder("st", "a", Buy, "1.0", "120.0")
+	stop.StopPrice = DecimalBig("110.0")
+	ob.Process(stop)
+	info = ob.GetOrder("st")
+	if info == nil || info.StopPrice.Cmp(DecimalBig("110.0")) != 0 || info.QueuePosition != -1 || info.Remaining.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("unexpected stop order info %+v", info)
+	}
+}
+
+func TestListOpenOrders(t *testing.T) {
+	ob := NewOrderBook(nil)
+	ob.Process(newBinaryOrder("b1", "a", Buy, "1.0", "90.0"))
+	ob.Process(newBinaryOrder("b2", "b", Buy, "1.0", "95.0"))
+	ob.Process(newBinaryOrder("b3", "a", Buy, "1.0", "95.0"))
+	ob.Process(newBinaryOrder("s1", "a", Sell, "1.0", "105.0"))
+	ob.Process(newBinaryOrder("s2", "a", Sell, "1.0", "101.0"))
+
+	ids := func(orders []OrderInfo) []string {
+		var res []string
+		for _, o := range orders {
+			res = append(res, o.ID)
+		}
+		return res
+	}
+
+	var tests = []struct {
+		filter   OpenOrdersFilter
+		expected []string
+	}{
+		{OpenOrdersFilter{}, []string{"b2", "b3", "b1", "s2", "s1"}},
+		{OpenOrdersFilter{Account: "a"}, []string{"b3", "b1", "s2", "s1"}},
+		{OpenOrdersFilter{Account: "a", Side: Sell}, []string{"s2", "s1"}},
+		{OpenOrdersFilter{Account: "c"}, nil},
+	}
+	for _, tt := range tests {
+		have := ids(ob.ListOpenOrders(tt.filter))
+		if len(have) != len(tt.expected) {
+			t.Fatalf("expected %v (have: %v)", tt.expected, have)
+		}
+		for i := range have {
+			if have[i] != tt.expected[i] {
+				t.Fatalf("expected %v (have: %v)", tt.expected, have)
+			}
+		}
+	}
+}
+
+func TestListOpenOrdersQueuePositions(t *testing.T) {
+	ob := NewOrderBook(nil)
+	ob.Process(newBinaryOrder("b1", "b", Buy, "1.0", "95.0"))
+	ob.Process(newBinaryOrder("b2", "a", Buy, "2.0", "95.0"))
+	ob.Process(newBinaryOrder("b3", "b", Buy, "3.0", "95.0"))
+	ob.Process(newBinaryOrder("b4", "a", Buy, "1.0", "95.0"))
+
+	// 过滤掉的订单同样计入队列位置
+	for _, info := range ob.ListOpenOrders(OpenOrdersFilter{Account: "a"}) {
+		want := ob.GetOrder(info.ID)
+		if info.QueuePosition != want.QueuePosition || info.VolumeAhead.Cmp(want.VolumeAhead) != 0 {
+			t.Fatalf("%s: queue position should match GetOrder (have: %d/%s, want: %d/%s)",
+				info.ID, info.QueuePosition, info.VolumeAhead, want.QueuePosition, want.VolumeAhead)
+		}
+	}
+	if info := ob.GetOrder("b4"); info.QueuePosition != 3 || info.VolumeAhead.Cmp(DecimalBig("6.0")) != 0 {
+		t.Fatalf("unexpected queue position (have: %d/%s)", info.QueuePosition, info.VolumeAhead)
+	}
+}
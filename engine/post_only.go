@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// PostOnlyMode 只做 Maker 订单的处理方式
+type PostOnlyMode string
+
+// PostOnlyNone 普通订单（默认）；PostOnlyReject 会吃单时拒绝；PostOnlyReprice 会吃单时改价到对手价外一个 tick
+const (
+	PostOnlyNone    PostOnlyMode = "none"
+	PostOnlyReject  PostOnlyMode = "reject"
+	PostOnlyReprice PostOnlyMode = "reprice"
+)
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (m PostOnlyMode) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON 实现 JSON 反序列化接口（空字符串视为 none）
+func (m *PostOnlyMode) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `""`, `"none"`:
+		*m = PostOnlyNone
+	case `"reject"`:
+		*m = PostOnlyReject
+	case `"reprice"`:
+		*m = PostOnlyReprice
+	default:
+		return &json.UnsupportedValueError{
+			Value: reflect.New(reflect.TypeOf(data)),
+			Str:   string(data),
+		}
+	}
+
+	return nil
+}
+
+// String 实现 Stringer 接口（未设置时返回 none）
+func (m PostOnlyMode) String() string {
+	switch m {
+	case PostOnlyReject:
+		return "reject"
+	case PostOnlyReprice:
+		return "reprice"
+	}
+	return "none"
+}
+
+// checkPostOnly 在撮合前检查 post-only 订单是否会吃掉对手盘 tree 的流动性
+// 会吃单时按模式拒绝（返回 false）或把价格改到对手最优价外一个 tick
+func (ob *OrderBook) checkPostOnly(order *Order, tree PriceLevels) bool {
+	best := ob.bestPrice(order.Type, tree)
+	if best == nil {
+		return true
+	}
+
+	if order.Type == Buy && order.Price.Cmp(best) == -1 || order.Type == Sell && order.Price.Cmp(best) == 1 {
+		return true
+	}
+
+	if order.PostOnly == PostOnlyReprice {
+		if order.Type == Buy {
+			order.Price = best.Sub(ob.spec.TickSize)
+		} else {
+			order.Price = best.Add(ob.spec.TickSize)
+		}
+		if order.Price.Cmp(decimalZero) == 1 {
+			return true
+		}
+	}
+	ob.rejectOrder(order, RejectPostOnlyWouldTake)
+	return false
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"testing"
+)
+
+type rejectListener struct {
+	MockListener
+	Rejected map[string]RejectReason
+}
+
+func (l *rejectListener) OnOrderRejected(id string, reason RejectReason) {
+	if l.Rejected == nil {
+		l.Rejected = map[string]RejectReason{}
+	}
+	l.Rejected[id] = reason
+}
+
+func TestPostOnly(t *testing.T) {
+	var tests = []struct {
+		mode     PostOnlyMode
+		side     Side
+		price    string
+		rejected bool
+		rest     string
+	}{
+		// 买一 7000，卖一 7100
+		{PostOnlyReject, Buy, "7050.0", false, "7050.0"},
+		{PostOnlyReject, Buy, "7100.0", true, ""},
+		{PostOnlyReject, Sell, "6900.0", true, ""},
+		{PostOnlyReprice, Buy, "7200.0", false, "7099.9"},
+		{PostOnlyReprice, Sell, "7000.0", false, "7000.1"},
+		{PostOnlyReprice, Sell, "7050.0", false, "7050.0"},
+	}
+
+	for i, tt := range tests {
+		listener := &rejectListener{}
+		ob := NewOrderBook(listener)
+		ob.SetTickSize(DecimalBig("0.1"))
+		ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("7000.0")))
+		ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("7100.0")))
+
+		order := NewOrder("p1", tt.side, DecimalBig("2.0"), DecimalBig(tt.price))
+		order.PostOnly = tt.mode
+		ob.Process(*order)
+
+		if len(listener.Trades) != 0 {
+			t.Fatalf("Case %d: post-only order should never trade", i)
+		}
+		if _, ok := listener.Rejected["p1"]; ok != tt.rejected {
+			t.Fatalf("Case %d: rejected (have: %v, want: %v)", i, ok, tt.rejected)
+		}
+		idx, ok := ob.lookup("p1")
+		if tt.rest == "" {
+			if ok {
+				t.Fatalf("Case %d: rejected order should not rest", i)
+			}
+			continue
+		}
+		if !ok || ob.Arena.Get(idx).Price.Cmp(DecimalBig(tt.rest)) != 0 {
+			t.Fatalf("Case %d: order should rest at %s", i, tt.rest)
+		}
+	}
+}
+
+func TestPostOnlyRejectWithoutRejectListener(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("7100.0")))
+
+	order := NewOrder("p1", Buy, DecimalBig("1.0"), DecimalBig("7100.0"))
+	order.PostOnly = PostOnlyReject
+	ob.Process(*order)
+
+	if len(listener.Cancelled) != 1 || listener.Cancelled[0] != "p1" {
+		t.Fatal("reject should fall back to OnOrderCancelled")
+	}
+}
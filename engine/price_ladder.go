@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// PriceLadder 价格有界品种（如价格在 0.001–0.999 之间的预测市场份额）的价格档位容器：
+// [min, max] 区间按最小变动价位预分配档位数组，以位图查找下一个非空档位，并维护最低 / 最高非空档位游标，
+// 最优价查询为 O(1)，插入与删除档位不涉及树的旋转
+// 区间外或不在价位网格上的价格落入溢出 PriceTree，撮合语义与 PriceTree 完全一致
+type PriceLadder struct {
+	base     int64 // 数组首个档位的价格（定点数）
+	tick     int64 // 相邻档位的价差（定点数）
+	arena    *LevelArena
+	levels   []IndexType // 下标 i 对应价格 base + i*tick 的档位（NullIndex 表示空档位）
+	bits     []uint64    // 非空档位位图
+	count    int         // 数组中的非空档位数
+	lo, hi   int         // 数组中最低 / 最高非空档位的下标（count 为 0 时无意义）
+	overflow *PriceTree
+}
+
+// MaxLadderLevels PriceLadder 预分配档位数的上限（约 1M 个档位）
+const MaxLadderLevels = 1 << 20
+
+// NewPriceLadder 返回覆盖 [min, max]、价位间隔为 tick 的价格档位数组，档位由 levels 分配，
+// 参数不合法或档位数超过 MaxLadderLevels 时返回 nil
+// 数组按 (max-min)/tick+1 个档位预分配，区间应与品种的价格范围相符
+func NewPriceLadder(levels *LevelArena, min, max, tick *util.StandardBigDecimal) *PriceLadder {
+	if min == nil || max == nil || tick == nil || tick.Val <= 0 || min.Cmp(max) == 1 {
+		return nil
+	}
+	span := max.Val - min.Val
+	if span < 0 || span/tick.Val >= MaxLadderLevels {
+		// 区间过大（或相减溢出）
+		return nil
+	}
+	n := int(span/tick.Val) + 1
+	l := &PriceLadder{
+		base:     min.Val,
+		tick:     tick.Val,
+		arena:    levels,
+		levels:   make([]IndexType, n),
+		bits:     make([]uint64, (n+63)/64),
+		overflow: NewPriceTree(levels),
+	}
+	for i := range l.levels {
+		l.levels[i] = NullIndex
+	}
+	return l
+}
+
+// WithPriceLadder 订单簿双边使用覆盖 [min, max]、价位间隔为 tick 的 PriceLadder
+// 参数不合法或档位数超过 MaxLadderLevels 属于配置错误，构造订单簿时 panic，不会静默退回 PriceTree
+func WithPriceLadder(min, max, tick *util.StandardBigDecimal) BookOption {
+	return func(ob *OrderBook) {
+		buys, sells := NewPriceLadder(ob.Levels, min, max, tick), NewPriceLadder(ob.Levels, min, max, tick)
+		if buys == nil {
+			panic(fmt.Sprintf("engine: invalid price ladder [%v, %v] / %v", min, max, tick))
+		}
+		ob.BuyTree, ob.SellTree = buys, sells
+	}
+}
+
+// index 返回价格在数组中的下标，区间外或不在价位网格上时返回 false
+func (l *PriceLadder) index(price int64) (int, bool) {
+	if price < l.base {
+		return 0, false
+	}
+	offset := price - l.base
+	if offset%l.tick != 0 || offset/l.tick >= int64(len(l.levels)) {
+		return 0, false
+	}
+	return int(offset / l.tick), true
+}
+
+// Len 返回价格档位数
+func (l *PriceLadder) Len() int {
+	return l.count + l.overflow.Len()
+}
+
+// Get 返回价格为 price 的档位，不存在时返回 nil
+func (l *PriceLadder) Get(price int64) *OrderNode {
+	if i, ok := l.index(price); ok {
+		return l.level(i)
+	}
+	return l.overflow.Get(price)
+}
+
+// level 返回下标 i 处的档位，空档位返回 nil
+func (l *PriceLadder) level(i int) *OrderNode {
+	if l.levels[i] == NullIndex {
+		return nil
+	}
+	return l.arena.Get(l.levels[i])
+}
+
+// Min 返回最低价档位，没有档位时返回 nil
+func (l *PriceLadder) Min() *OrderNode {
+	min := l.overflow.Min()
+	if l.count > 0 && (min == nil || l.level(l.lo).price < min.price) {
+		return l.level(l.lo)
+	}
+	return min
+}
+
+// Max 返回最高价档位，没有档位时返回 nil
+func (l *PriceLadder) Max() *OrderNode {
+	max := l.overflow.Max()
+	if l.count > 0 && (max == nil || l.level(l.hi).price > max.price) {
+		return l.level(l.hi)
+	}
+	return max
+}
+
+// Insert 插入价格为 price 的档位（调用方保证该价格尚无档位）
+func (l *PriceLadder) Insert(price int64, level *OrderNode) {
+	i, ok := l.index(price)
+	if !ok {
+		l.overflow.Insert(price, level)
+		return
+	}
+	level.price = price
+	l.levels[i] = level.index
+	l.bits[i>>6] |= 1 << (uint(i) & 63)
+	if l.count == 0 || i < l.lo {
+		l.lo = i
+	}
+	if l.count == 0 || i > l.hi {
+		l.hi = i
+	}
+	l.count++
+}
+
+// Remove 移除价格为 price 的档位并返回，不存在时返回 nil
+func (l *PriceLadder) Remove(price int64) *OrderNode {
+	i, ok := l.index(price)
+	if !ok {
+		return l.overflow.Remove(price)
+	}
+	level := l.level(i)
+	if level == nil {
+		return nil
+	}
+	l.levels[i] = NullIndex
+	l.bits[i>>6] &^= 1 << (uint(i) & 63)
+	l.count--
+	if l.count > 0 {
+		// 移除的是最优档位时沿位图移动游标
+		if i == l.lo {
+			l.lo = l.next(i)
+		}
+		if i == l.hi {
+			l.hi = l.prev(i)
+		}
+	}
+	return level
+}
+
+// Walk 按价格顺序遍历档位（ascending 为 true 时由低到高），fn 返回 false 时提前结束
+// 数组档位与溢出档位按价格归并
+func (l *PriceLadder) Walk(ascending bool, fn func(price int64, level *OrderNode) bool) {
+	i, step := l.lo, l.next
+	other := l.overflow.Min()
+	if !ascending {
+		i, step = l.hi, l.prev
+		other = l.overflow.Max()
+	}
+	if l.count == 0 {
+		i = -1
+	}
+	for i >= 0 || other != nil {
+		var level *OrderNode
+		if i >= 0 {
+			level = l.level(i)
+		}
+		if level == nil || other != nil && (ascending && other.price < level.price || !ascending && other.price > level.price) {
+			if !fn(other.price, other) {
+				return
+			}
+			other = l.overflow.neighbor(other.price, ascending)
+			continue
+		}
+		if !fn(level.price, level) {
+			return
+		}
+		i = step(i)
+	}
+}
+
+// next 返回下标大于 i 的最低非空档位下标，不存在时返回 -1
+func (l *PriceLadder) next(i int) int {
+	i++
+	for w := i >> 6; w < len(l.bits); w++ {
+		word := l.bits[w]
+		if w == i>>6 {
+			word &= ^uint64(0) << (uint(i) & 63)
+		}
+		if word != 0 {
+			return w<<6 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// prev 返回下标小于 i 的最高非空档位下标，不存在时返回 -1
+func (l *PriceLadder) prev(i int) int {
+	i--
+	if i < 0 {
+		return -1
+	}
+	for w := i >> 6; w >= 0; w-- {
+		word := l.bits[w]
+		if w == i>>6 {
+			word &= ^uint64(0) >> (63 - uint(i)&63)
+		}
+		if word != 0 {
+			return w<<6 + 63 - bits.LeadingZeros64(word)
+		}
+	}
+	return -1
+}
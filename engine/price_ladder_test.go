@@ -0,0 +1,236 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// TestPriceLevelBackendsAgree 以相同的随机操作序列分别驱动 PriceTree 与 PriceLadder 订单簿，
+// 两者的事件流与剩余挂单应完全一致（价格在阶梯区间内外、网格上下混合，区间外与不在网格上的价格走溢出树）
+func TestPriceLevelBackendsAgree(t *testing.T) {
+	backends := []struct {
+		name string
+		opts []BookOption
+	}{
+		{"tree", nil},
+		{"ladder", []BookOption{WithPriceLadder(DecimalBig("90"), DecimalBig("110"), DecimalBig("0.1"))}},
+	}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var want []Event
+	var wantOpen []OrderInfo
+	for _, backend := range backends {
+		listener := &eventListener{}
+		ob := NewOrderBook(listener, append(backend.opts, WithClock(func() time.Time { return clock }))...)
+		runRandomOrders(ob, rand.New(rand.NewSource(1)), 3000)
+		open := ob.ListOpenOrders(OpenOrdersFilter{})
+		if want == nil {
+			want, wantOpen = listener.Events, open
+			continue
+		}
+		if !reflect.DeepEqual(listener.Events, want) {
+			t.Fatalf("%s: event stream should match the tree backend (have %d events, want %d)", backend.name, len(listener.Events), len(want))
+		}
+		if !reflect.DeepEqual(open, wantOpen) {
+			t.Fatalf("%s: open orders should match the tree backend", backend.name)
+		}
+	}
+	if len(want) < 3000 || len(wantOpen) == 0 {
+		t.Fatalf("random orders should trade and leave resting orders (have %d events, %d open)", len(want), len(wantOpen))
+	}
+}
+
+// runRandomOrders 向订单簿提交 n 笔随机操作：限价单、市价单、冰山单、条件单、FOK、post-only、撤单与改单
+func runRandomOrders(ob *OrderBook, r *rand.Rand, n int) {
+	tick := DecimalBig("0.1").Val
+	price := func() *util.StandardBigDecimal {
+		val := DecimalBig("85").Val + r.Int63n(300)*tick
+		if r.Intn(5) == 0 {
+			val += tick / 2
+		}
+		return &util.StandardBigDecimal{Val: val}
+	}
+	amount := func() *util.StandardBigDecimal {
+		return &util.StandardBigDecimal{Val: (r.Int63n(5) + 1) * DecimalBig("1").Val}
+	}
+	side := func() Side {
+		if r.Intn(2) == 0 {
+			return Buy
+		}
+		return Sell
+	}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("o-%d", i)
+		order := NewOrder(id, side(), amount(), price())
+		switch r.Intn(10) {
+		case 4:
+			order.Price = decimalZero
+			ob.ProcessMarket(*order)
+		case 5:
+			ob.CancelOrder(fmt.Sprintf("o-%d", r.Intn(i+1)))
+		case 6:
+			ob.AmendOrder(fmt.Sprintf("o-%d", r.Intn(i+1)), price(), nil)
+		case 7:
+			order.DisplayAmount = &util.StandardBigDecimal{Val: DecimalBig("1").Val}
+			ob.Process(*order)
+		case 8:
+			order.StopPrice = price()
+			ob.Process(*order)
+		case 9:
+			if r.Intn(2) == 0 {
+				order.TimeInForce = FOK
+			} else {
+				order.PostOnly = PostOnlyReprice
+			}
+			ob.Process(*order)
+		default:
+			ob.Process(*order)
+		}
+	}
+}
+
+func TestNewPriceLadder(t *testing.T) {
+	var tests = []struct {
+		min, max, tick string
+		levels         int
+	}{
+		{"0.001", "0.999", "0.001", 999},
+		{"0", "1", "0.25", 5},
+		{"1", "1", "0.1", 1},
+		{"1", "0", "0.1", 0},
+		{"0", "1", "0", 0},
+		{"0", "1000000", "0.0001", 0},
+	}
+	for _, tt := range tests {
+		ladder := NewPriceLadder(NewLevelArena(), DecimalBig(tt.min), DecimalBig(tt.max), DecimalBig(tt.tick))
+		if tt.levels == 0 {
+			if ladder != nil {
+				t.Fatalf("ladder [%s, %s] / %s should be rejected", tt.min, tt.max, tt.tick)
+			}
+			continue
+		}
+		if ladder == nil || len(ladder.levels) != tt.levels {
+			t.Fatalf("ladder [%s, %s] / %s should have %d levels", tt.min, tt.max, tt.tick, tt.levels)
+		}
+	}
+}
+
+func TestPriceLadderCursors(t *testing.T) {
+	levels := NewLevelArena()
+	ladder := NewPriceLadder(levels, DecimalBig("0.001"), DecimalBig("0.999"), DecimalBig("0.001"))
+	r := rand.New(rand.NewSource(1))
+	prices := map[int64]bool{}
+	for len(prices) < 300 {
+		// 网格内、网格外与区间外的价格混合
+		price := (r.Int63n(1200) + 1) * DecimalBig("0.001").Val
+		if r.Intn(5) == 0 {
+			price += r.Int63n(DecimalBig("0.001").Val)
+		}
+		if prices[price] {
+			continue
+		}
+		prices[price] = true
+		ladder.Insert(price, levels.Get(levels.Alloc()))
+	}
+
+	for price := range prices {
+		if r.Intn(2) == 0 {
+			if ladder.Remove(price) == nil {
+				t.Fatalf("level %d should be removed", price)
+			}
+			delete(prices, price)
+		}
+		if ladder.Remove(price+DecimalBig("5").Val) != nil {
+			t.Fatal("missing level should not be removed")
+		}
+	}
+
+	var expected []int64
+	for price := range prices {
+		expected = append(expected, price)
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+	if ladder.Len() != len(expected) || ladder.overflow.Len() == 0 {
+		t.Fatalf("unexpected level count (have: %d, want: %d)", ladder.Len(), len(expected))
+	}
+	if ladder.Min().price != expected[0] || ladder.Max().price != expected[len(expected)-1] {
+		t.Fatalf("unexpected best levels (min: %d, max: %d)", ladder.Min().price, ladder.Max().price)
+	}
+
+	for _, ascending := range []bool{true, false} {
+		var have []int64
+		ladder.Walk(ascending, func(price int64, level *OrderNode) bool {
+			if ladder.Get(price) != level {
+				t.Fatalf("level %d should be found", price)
+			}
+			have = append(have, price)
+			return true
+		})
+		if len(have) != len(expected) {
+			t.Fatalf("walk should visit %d levels (have: %d)", len(expected), len(have))
+		}
+		for i := range have {
+			want := expected[i]
+			if !ascending {
+				want = expected[len(expected)-1-i]
+			}
+			if have[i] != want {
+				t.Fatalf("walk out of order at %d (have: %d, want: %d)", i, have[i], want)
+			}
+		}
+	}
+
+	// 逐个移除最优档位，游标沿位图前进
+	for _, price := range expected {
+		if ladder.Min().price != price {
+			t.Fatalf("best level should be %d (have: %d)", price, ladder.Min().price)
+		}
+		ladder.Remove(price)
+	}
+	if ladder.Len() != 0 || ladder.Min() != nil || ladder.Max() != nil {
+		t.Fatal("ladder should be empty")
+	}
+}
+
+func TestPriceLadderBook(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener, WithPriceLadder(DecimalBig("0.001"), DecimalBig("0.999"), DecimalBig("0.001")))
+	if _, ok := ob.BuyTree.(*PriceLadder); !ok {
+		t.Fatal("book should use the price ladder")
+	}
+	ob.Process(newBinaryOrder("s1", "", Sell, "1.0", "0.6"))
+	ob.Process(newBinaryOrder("s2", "", Sell, "1.0", "0.55"))
+	ob.Process(newBinaryOrder("s3", "", Sell, "1.0", "0.5505"))
+	ob.Process(newBinaryOrder("b1", "", Buy, "3.5", "0.6"))
+
+	if len(listener.Trades) != 3 || listener.Trades[0].MakerID != "s2" || listener.Trades[1].MakerID != "s3" || listener.Trades[2].MakerID != "s1" {
+		t.Fatalf("buy should sweep asks by price including off-grid levels (have: %+v)", listener.Trades)
+	}
+	if ob.SellTree.Len() != 0 || ob.BuyTree.Max().price != DecimalBig("0.6").Val {
+		t.Fatal("remainder should rest at 0.6")
+	}
+	ob.CancelOrder("b1")
+	if ob.BuyTree.Len() != 0 {
+		t.Fatal("cancel should remove the level")
+	}
+
+}
+
+func TestWithPriceLadderInvalid(t *testing.T) {
+	for _, args := range [][3]string{{"1", "0", "0.1"}, {"0", "1000000", "0.0001"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("ladder %v should be rejected as a misconfiguration", args)
+				}
+			}()
+			NewOrderBook(nil, WithPriceLadder(DecimalBig(args[0]), DecimalBig(args[1]), DecimalBig(args[2])))
+		}()
+	}
+}
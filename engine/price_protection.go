@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"github.com/goovo/matching-engine/util"
+)
+
+// setPriceLimit 根据保护价与最大滑点计算市价单本次撮合的价格边界（取两者中更严格的一个）
+// 最大滑点以撮合前对手最优价为基准：买单上限 best × (1 + p%)，卖单下限 best × (1 - p%)
+func (ob *OrderBook) setPriceLimit(order *Order, tree PriceLevels) {
+	order.priceLimit = order.ProtectionPrice
+	if order.MaxSlippage == nil {
+		return
+	}
+	best := ob.bestPrice(order.Type, tree)
+	if best == nil {
+		return
+	}
+
+	offset := &util.StandardBigDecimal{Val: best.MulFloor(order.MaxSlippage).Val / 100}
+	limit := best.Add(offset)
+	if order.Type == Sell {
+		limit = best.Sub(offset)
+	}
+	if order.priceLimit == nil || order.withinLimit(limit) {
+		order.priceLimit = limit
+	}
+}
+
+// withinLimit 判断价格 price 是否在市价单的价格边界内
+func (order *Order) withinLimit(price *util.StandardBigDecimal) bool {
+	if order.Type == Sell {
+		return price.Cmp(order.priceLimit) != -1
+	}
+	return price.Cmp(order.priceLimit) != 1
+}
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"testing"
+)
+
+type cancelReasonListener struct {
+	MockListener
+	Reasons map[string]CancelReason
+}
+
+func (l *cancelReasonListener) OnOrderCancelReason(id string, reason CancelReason) {
+	if l.Reasons == nil {
+		l.Reasons = map[string]CancelReason{}
+	}
+	l.Reasons[id] = reason
+}
+
+func newProtectionBook() (*OrderBook, *cancelReasonListener) {
+	listener := &cancelReasonListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("110.0")))
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("90.0")))
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("80.0")))
+	return ob, listener
+}
+
+func TestMarketProtectionPrice(t *testing.T) {
+	ob, listener := newProtectionBook()
+	order := NewOrder("m", Buy, DecimalBig("3.0"), DecimalBig("1.0"))
+	order.ProtectionPrice = DecimalBig("101.0")
+	ob.ProcessMarket(*order)
+
+	if len(listener.Trades) != 2 {
+		t.Fatalf("sweep should stop at protection price (have: %+v)", listener.Trades)
+	}
+	if listener.Reasons["m"] != CancelPriceProtection || len(listener.Cancelled) != 1 {
+		t.Fatalf("remainder should be cancelled with reason (have: %v)", listener.Reasons)
+	}
+	if _, ok := ob.lookup("s3"); !ok {
+		t.Fatal("level beyond protection price should be untouched")
+	}
+}
+
+func TestMarketMaxSlippage(t *testing.T) {
+	var tests = []struct {
+		side     Side
+		slippage string
+		trades   int
+	}{
+		{Buy, "1.0", 2},   // 100 × 1.01 = 101
+		{Buy, "0.5", 1},   // 100.5
+		{Buy, "10.0", 3},  // 110
+		{Sell, "10.0", 1}, // 90 × 0.9 = 81
+		{Sell, "20.0", 2}, // 72
+	}
+
+	for _, tt := range tests {
+		ob, listener := newProtectionBook()
+		order := NewOrder("m", tt.side, DecimalBig("3.0"), DecimalBig("1.0"))
+		order.MaxSlippage = DecimalBig(tt.slippage)
+		ob.ProcessMarket(*order)
+		if len(listener.Trades) != tt.trades {
+			t.Fatalf("%s %s%%: unexpected trades (have: %d, want: %d)", tt.side, tt.slippage, len(listener.Trades), tt.trades)
+		}
+	}
+}
+
+func TestMarketProtectionStricterWins(t *testing.T) {
+	ob, listener := newProtectionBook()
+	order := NewOrder("m", Buy, DecimalBig("3.0"), DecimalBig("1.0"))
+	order.ProtectionPrice = DecimalBig("100.0")
+	order.MaxSlippage = DecimalBig("10.0")
+	ob.ProcessMarket(*order)
+	if len(listener.Trades) != 1 {
+		t.Fatalf("stricter bound should apply (have: %d trades)", len(listener.Trades))
+	}
+}
+
+func TestMarketProtectionFOK(t *testing.T) {
+	ob, listener := newProtectionBook()
+	order := NewOrder("m", Buy, DecimalBig("3.0"), DecimalBig("1.0"))
+	order.ProtectionPrice = DecimalBig("101.0")
+	order.TimeInForce = FOK
+	ob.ProcessMarket(*order)
+	if len(listener.Trades) != 0 || len(listener.Cancelled) != 1 {
+		t.Fatal("FOK should only count depth within protection price")
+	}
+}
+
+func TestMarketProtectionJSON(t *testing.T) {
+	var order Order
+	if err := order.FromJSON([]byte(`{"id":"m","type":"buy","amount":"1.0","price":"1.0","protection_price":"101.5","max_slippage":"0.5"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if order.ProtectionPrice.Cmp(DecimalBig("101.5")) != 0 || order.MaxSlippage.Cmp(DecimalBig("0.5")) != 0 {
+		t.Fatalf("unexpected order (have: %+v)", order)
+	}
+}
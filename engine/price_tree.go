@@ -0,0 +1,229 @@
+package engine
+
+// PriceLevels 单边订单簿的价格档位容器：以精确定点价格存取档位，并按价格顺序查找与遍历
+// 默认实现为 PriceTree，价格有界的品种可通过 WithPriceLadder 使用 PriceLadder
+type PriceLevels interface {
+	// Len 返回价格档位数
+	Len() int
+	// Get 返回价格为 price 的档位，不存在时返回 nil
+	Get(price int64) *OrderNode
+	// Min / Max 返回最低 / 最高价档位，没有档位时返回 nil
+	Min() *OrderNode
+	Max() *OrderNode
+	// Insert 插入价格为 price 的档位（调用方保证该价格尚无档位）
+	Insert(price int64, level *OrderNode)
+	// Remove 移除价格为 price 的档位并返回，不存在时返回 nil
+	Remove(price int64) *OrderNode
+	// Walk 按价格顺序遍历档位（ascending 为 true 时由低到高），fn 返回 false 时提前结束
+	Walk(ascending bool, fn func(price int64, level *OrderNode) bool)
+}
+
+// PriceTree 单边订单簿的价格档位树：以精确定点价格（StandardBigDecimal.Val）为键的 AVL 树，
+// 不经过浮点转换，相差 1e-8 的价格也各自成档
+// 树节点即 LevelArena 中的价格档位本身，以索引互相链接，插入与删除档位不产生额外分配
+type PriceTree struct {
+	levels *LevelArena
+	root   IndexType
+	size   int
+}
+
+// NewPriceTree 返回空的价格档位树，档位由 levels 分配
+func NewPriceTree(levels *LevelArena) *PriceTree {
+	return &PriceTree{levels: levels, root: NullIndex}
+}
+
+// Len 返回价格档位数
+func (t *PriceTree) Len() int {
+	return t.size
+}
+
+// Get 返回价格为 price 的档位，不存在时返回 nil
+func (t *PriceTree) Get(price int64) *OrderNode {
+	for idx := t.root; idx != NullIndex; {
+		n := t.levels.Get(idx)
+		switch {
+		case price < n.price:
+			idx = n.left
+		case price > n.price:
+			idx = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Min 返回最低价档位，树为空时返回 nil
+func (t *PriceTree) Min() *OrderNode {
+	if t.root == NullIndex {
+		return nil
+	}
+	n := t.levels.Get(t.root)
+	for n.left != NullIndex {
+		n = t.levels.Get(n.left)
+	}
+	return n
+}
+
+// Max 返回最高价档位，树为空时返回 nil
+func (t *PriceTree) Max() *OrderNode {
+	if t.root == NullIndex {
+		return nil
+	}
+	n := t.levels.Get(t.root)
+	for n.right != NullIndex {
+		n = t.levels.Get(n.right)
+	}
+	return n
+}
+
+// Insert 插入价格为 price 的档位（调用方保证该价格尚无档位）
+func (t *PriceTree) Insert(price int64, level *OrderNode) {
+	level.price = price
+	t.root = t.insert(t.root, level)
+	t.size++
+}
+
+// Remove 移除价格为 price 的档位并返回，不存在时返回 nil
+func (t *PriceTree) Remove(price int64) *OrderNode {
+	level := t.Get(price)
+	if level == nil {
+		return nil
+	}
+	t.root = t.remove(t.root, price)
+	level.left, level.right, level.height = NullIndex, NullIndex, 0
+	t.size--
+	return level
+}
+
+// Walk 按价格顺序遍历档位（ascending 为 true 时由低到高），fn 返回 false 时提前结束
+func (t *PriceTree) Walk(ascending bool, fn func(price int64, level *OrderNode) bool) {
+	t.walk(t.root, ascending, fn)
+}
+
+// neighbor 返回价格严格高于（ascending）或严格低于 price 的最近档位，不存在时返回 nil
+func (t *PriceTree) neighbor(price int64, ascending bool) *OrderNode {
+	var best *OrderNode
+	for idx := t.root; idx != NullIndex; {
+		n := t.levels.Get(idx)
+		if ascending && n.price > price || !ascending && n.price < price {
+			best = n
+		}
+		if n.price > price || !ascending && n.price == price {
+			idx = n.left
+		} else {
+			idx = n.right
+		}
+	}
+	return best
+}
+
+func (t *PriceTree) walk(idx IndexType, ascending bool, fn func(price int64, level *OrderNode) bool) bool {
+	if idx == NullIndex {
+		return true
+	}
+	n := t.levels.Get(idx)
+	first, second := n.left, n.right
+	if !ascending {
+		first, second = n.right, n.left
+	}
+	return t.walk(first, ascending, fn) && fn(n.price, n) && t.walk(second, ascending, fn)
+}
+
+func (t *PriceTree) height(idx IndexType) int8 {
+	if idx == NullIndex {
+		return 0
+	}
+	return t.levels.Get(idx).height
+}
+
+// fixHeight 由子树高度重新计算节点高度
+func (t *PriceTree) fixHeight(n *OrderNode) {
+	l, r := t.height(n.left), t.height(n.right)
+	if l < r {
+		l = r
+	}
+	n.height = l + 1
+}
+
+func (t *PriceTree) rotateLeft(n *OrderNode) IndexType {
+	r := t.levels.Get(n.right)
+	n.right, r.left = r.left, n.index
+	t.fixHeight(n)
+	t.fixHeight(r)
+	return r.index
+}
+
+func (t *PriceTree) rotateRight(n *OrderNode) IndexType {
+	l := t.levels.Get(n.left)
+	n.left, l.right = l.right, n.index
+	t.fixHeight(n)
+	t.fixHeight(l)
+	return l.index
+}
+
+// rebalance 恢复以 n 为根的子树的 AVL 平衡，返回新的子树根
+func (t *PriceTree) rebalance(n *OrderNode) IndexType {
+	t.fixHeight(n)
+	switch diff := t.height(n.left) - t.height(n.right); {
+	case diff > 1:
+		if l := t.levels.Get(n.left); t.height(l.left) < t.height(l.right) {
+			n.left = t.rotateLeft(l)
+		}
+		return t.rotateRight(n)
+	case diff < -1:
+		if r := t.levels.Get(n.right); t.height(r.right) < t.height(r.left) {
+			n.right = t.rotateRight(r)
+		}
+		return t.rotateLeft(n)
+	}
+	return n.index
+}
+
+func (t *PriceTree) insert(idx IndexType, level *OrderNode) IndexType {
+	if idx == NullIndex {
+		level.left, level.right, level.height = NullIndex, NullIndex, 1
+		return level.index
+	}
+	n := t.levels.Get(idx)
+	if level.price < n.price {
+		n.left = t.insert(n.left, level)
+	} else {
+		n.right = t.insert(n.right, level)
+	}
+	return t.rebalance(n)
+}
+
+func (t *PriceTree) remove(idx IndexType, price int64) IndexType {
+	if idx == NullIndex {
+		return NullIndex
+	}
+	n := t.levels.Get(idx)
+	switch {
+	case price < n.price:
+		n.left = t.remove(n.left, price)
+	case price > n.price:
+		n.right = t.remove(n.right, price)
+	default:
+		if n.left == NullIndex {
+			return n.right
+		}
+		if n.right == NullIndex {
+			return n.left
+		}
+		// 用右子树的最低价档位替换被删除的档位
+		right, successor := t.removeMin(n.right)
+		successor.left, successor.right = n.left, right
+		n = successor
+	}
+	return t.rebalance(n)
+}
+
+func (t *PriceTree) removeMin(idx IndexType) (rest IndexType, min *OrderNode) {
+	n := t.levels.Get(idx)
+	if n.left == NullIndex {
+		return n.right, n
+	}
+	n.left, min = t.removeMin(n.left)
+	return t.rebalance(n), min
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkPriceTree 校验 AVL 不变式（有序、高度正确、平衡因子不超过 1），返回子树高度
+func checkPriceTree(t *testing.T, tree *PriceTree, idx IndexType, lo, hi int64) int8 {
+	if idx == NullIndex {
+		return 0
+	}
+	n := tree.levels.Get(idx)
+	if n.price < lo || n.price > hi {
+		t.Fatalf("price %d out of order (range: %d..%d)", n.price, lo, hi)
+	}
+	l := checkPriceTree(t, tree, n.left, lo, n.price-1)
+	r := checkPriceTree(t, tree, n.right, n.price+1, hi)
+	if l-r > 1 || r-l > 1 {
+		t.Fatalf("level %d is unbalanced (left: %d, right: %d)", n.price, l, r)
+	}
+	h := l
+	if r > h {
+		h = r
+	}
+	if n.height != h+1 {
+		t.Fatalf("level %d has wrong height (have: %d, want: %d)", n.price, n.height, h+1)
+	}
+	return n.height
+}
+
+func TestPriceTree(t *testing.T) {
+	levels := NewLevelArena()
+	tree := NewPriceTree(levels)
+	if tree.Min() != nil || tree.Max() != nil || tree.Remove(1) != nil {
+		t.Fatal("empty tree should have no levels")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	prices := map[int64]bool{}
+	for len(prices) < 500 {
+		price := r.Int63n(2000) + 1
+		if prices[price] {
+			continue
+		}
+		prices[price] = true
+		tree.Insert(price, levels.Get(levels.Alloc()))
+	}
+	for price := range prices {
+		if price%3 == 0 {
+			if tree.Remove(price) == nil {
+				t.Fatalf("level %d should be removed", price)
+			}
+			delete(prices, price)
+		}
+	}
+	checkPriceTree(t, tree, tree.root, math.MinInt64, math.MaxInt64)
+
+	var expected []int64
+	for price := range prices {
+		expected = append(expected, price)
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+	if tree.Len() != len(expected) || tree.Min().price != expected[0] || tree.Max().price != expected[len(expected)-1] {
+		t.Fatalf("unexpected tree bounds (len: %d, min: %d, max: %d)", tree.Len(), tree.Min().price, tree.Max().price)
+	}
+
+	var have []int64
+	tree.Walk(false, func(price int64, level *OrderNode) bool {
+		if tree.Get(price) != level {
+			t.Fatalf("level %d should be found", price)
+		}
+		have = append(have, price)
+		return true
+	})
+	for i := range have {
+		if have[i] != expected[len(expected)-1-i] {
+			t.Fatalf("descending walk out of order at %d (have: %d, want: %d)", i, have[i], expected[len(expected)-1-i])
+		}
+	}
+}
+
+func TestAdjacentTickPriceLevels(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newBinaryOrder("b1", "", Buy, "1.0", "0.5"))
+	ob.Process(newBinaryOrder("b2", "", Buy, "2.0", "0.50000001"))
+	ob.Process(newBinaryOrder("s1", "", Sell, "3.0", "0.50000003"))
+	ob.Process(newBinaryOrder("s2", "", Sell, "4.0", "0.50000002"))
+
+	if ob.BuyTree.Len() != 2 || ob.SellTree.Len() != 2 {
+		t.Fatalf("adjacent ticks should be separate levels (buys: %d, sells: %d)", ob.BuyTree.Len(), ob.SellTree.Len())
+	}
+	depth := ob.GetOrders(0)
+	expected := &BookArray{
+		Buys:  [][]string{{"0.50000001", "2"}, {"0.5", "1"}},
+		Sells: [][]string{{"0.50000002", "4"}, {"0.50000003", "3"}},
+	}
+	for i, level := range expected.Buys {
+		if depth.Buys[i][0] != level[0] || depth.Buys[i][1] != level[1] {
+			t.Fatalf("unexpected bid level %d (have: %v)", i, depth.Buys[i])
+		}
+	}
+	for i, level := range expected.Sells {
+		if depth.Sells[i][0] != level[0] || depth.Sells[i][1] != level[1] {
+			t.Fatalf("unexpected ask level %d (have: %v)", i, depth.Sells[i])
+		}
+	}
+
+	// 限价恰好为较优档位时只能吃到该档，不会越过相差 1e-8 的下一档
+	ob.Process(newBinaryOrder("s3", "", Sell, "5.0", "0.50000001"))
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "b2" || listener.Trades[0].Price != DecimalBig("0.50000001").Val {
+		t.Fatalf("sell should only match the 0.50000001 bid (have: %+v)", listener.Trades)
+	}
+	if info := ob.GetOrder("s3"); info == nil || info.Remaining.Cmp(DecimalBig("3.0")) != 0 || info.Price.Cmp(DecimalBig("0.50000001")) != 0 {
+		t.Fatalf("remainder should rest at 0.50000001 (have: %+v)", info)
+	}
+	if ob.BuyTree.Get(DecimalBig("0.50000001").Val) != nil || ob.SellTree.Min().price != DecimalBig("0.50000001").Val {
+		t.Fatal("filled bid level should be removed and the remainder should be the best ask")
+	}
+}
+
+func TestLargePriceLevels(t *testing.T) {
+	// 超过 2^53 的定点数在 float64 下无法区分相邻价格
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newBinaryOrder("s1", "", Sell, "0.01", "90000000000.00000002"))
+	ob.Process(newBinaryOrder("s2", "", Sell, "0.01", "90000000000.00000001"))
+	ob.Process(newBinaryOrder("b1", "", Buy, "0.01", "90000000000"))
+
+	if ob.SellTree.Len() != 2 {
+		t.Fatalf("large adjacent prices should be separate levels (have: %d)", ob.SellTree.Len())
+	}
+	depth := ob.GetOrders(1)
+	if len(depth.Sells) != 1 || depth.Sells[0][0] != "90000000000.00000001" || depth.Buys[0][0] != "90000000000" {
+		t.Fatalf("unexpected depth %+v", depth)
+	}
+	if len(listener.Trades) != 0 {
+		t.Fatalf("bid below the best ask should not trade (have: %+v)", listener.Trades)
+	}
+
+	ob.Process(newBinaryOrder("b2", "", Buy, "0.02", "90000000000.00000001"))
+	if len(listener.Trades) != 1 || listener.Trades[0].MakerID != "s2" || listener.Trades[0].Price != DecimalBig("90000000000.00000001").Val {
+		t.Fatalf("buy should only match the lower ask (have: %+v)", listener.Trades)
+	}
+	if best := ob.bestPrice(Sell, ob.BuyTree); best == nil || best.Cmp(DecimalBig("90000000000.00000001")) != 0 {
+		t.Fatalf("remainder should be the best bid (have: %v)", best)
+	}
+
+	ob.ProcessMarket(newBinaryOrder("m1", "", Buy, "0.01", "0"))
+	if len(listener.Trades) != 2 || listener.Trades[1].MakerID != "s1" || listener.Trades[1].Price != DecimalBig("90000000000.00000002").Val {
+		t.Fatalf("market order should fill at the exact ask (have: %+v)", listener.Trades)
+	}
+	if ob.SellTree.Len() != 0 {
+		t.Fatal("ask side should be empty")
+	}
+}
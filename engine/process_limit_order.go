@@ -3,7 +3,6 @@ package engine
 import (
 	// "fmt"
 
-	"github.com/goovo/binarytree"
 	"github.com/goovo/matching-engine/util"
 )
 
@@ -13,98 +12,186 @@ var decimalZero, _ = util.NewDecimalFromString("0.0")
 func (ob *OrderBook) Process(order Order) {
 	ob.mutex.Lock()
 	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
 
-	if order.Type == Buy {
-		// return ob.processOrderB(order)
-		ob.commonProcess(order, ob.SellTree, ob.addBuyOrder, ob.removeSellNode)
+	if order.StopPrice != nil {
+		// 止损限价单：触发后按限价单处理
+		ob.addStopOrder(order, false)
 	} else {
-		// return ob.processOrderS(order)
-		ob.commonProcess(order, ob.BuyTree, ob.addSellOrder, ob.removeBuyNode)
+		ob.process(order)
 	}
+	ob.triggerStops()
 }
 
-func (ob *OrderBook) commonProcess(order Order, tree *binarytree.BinaryTree, add func(Order), remove func(float64) error) {
-	var maxNode *binarytree.BinaryNode
+// process 限价单撮合（调用方持有锁）
+func (ob *OrderBook) process(order Order) {
+	tree, add := ob.SellTree, ob.addBuyOrder
 	if order.Type == Sell {
-		maxNode = tree.Max()
-	} else {
-		maxNode = tree.Min()
+		tree, add = ob.BuyTree, ob.addSellOrder
+	}
+
+	order.enter()
+	if ob.rejectDuplicate(&order) || ob.rejectInvalid(&order, false) || ob.rejectNotTrading(&order) {
+		return
 	}
-	if maxNode == nil {
+	if ob.state == StateAuction {
+		// 集合竞价：只挂单，交叉订单留待 Uncross 统一撮合
+		if order.TimeInForce == IOC || order.TimeInForce == FOK {
+			ob.rejectOrder(&order, RejectAuction)
+			return
+		}
 		add(order)
 		return
 	}
 
-	count := 0
-	noMoreOrders := false
-	
-	// 不需要 Clone 了，因为不需要返回 partialOrder
-	// orderOriginalAmount := order.Amount.Clone() 
-	
-	for maxNode == nil || order.Amount.Cmp(decimalZero) == 1 {
-		count++
-		if order.Type == Sell {
-			maxNode = tree.Max()
-		} else {
-			maxNode = tree.Min()
+	switch order.TimeInForce {
+	case IOC:
+		// 剩余部分不挂单，直接撤销
+		add = ob.cancelRemainder
+	case FOK:
+		// 先检查对手盘深度，不能全部成交则不产生任何成交
+		if !ob.canFill(&order, tree, false) {
+			ob.cancelWithReason(&order, CancelFillOrKill)
+			return
 		}
-		if maxNode == nil || noMoreOrders {
-			if order.Amount.Cmp(decimalZero) == 1 {
-				add(order)
-				break
-			} else {
-				break
+		add = ob.cancelRemainder
+	}
+	ob.commonProcess(&order, tree, add)
+}
+
+// cancelRemainder 撤销未成交的剩余部分（IOC/FOK 不挂单）
+func (ob *OrderBook) cancelRemainder(order Order) {
+	ob.cancelWithReason(&order, CancelImmediateOrCancel)
+}
+
+// canFill 判断对手盘在价格范围内的可成交量能否完全满足订单（FOK 预检查）
+// market 为 true 时不检查价格（市价单设置了保护价时按价格边界检查）
+func (ob *OrderBook) canFill(taker *Order, tree PriceLevels, market bool) bool {
+	// 遍历回调捕获订单副本，避免调用方的订单逃逸到堆上
+	order := *taker
+	var orderPrice int64
+	if order.Price != nil {
+		orderPrice = order.Price.Val
+	}
+	if market && order.priceLimit != nil {
+		orderPrice = order.priceLimit.Val
+	}
+	remaining := order.Amount.Val
+	if order.Funds != nil {
+		remaining = order.Funds.Val
+	}
+	var groups []*orderGroup // 已计入流动性的 OCO 订单组
+	stopped := false         // Taker 会被自成交防护撤销
+	tree.Walk(order.Type == Buy, func(price int64, node *OrderNode) bool {
+		if !market || order.priceLimit != nil {
+			if order.Type == Buy && price > orderPrice {
+				return false
+			}
+			if order.Type == Sell && price < orderPrice {
+				return false
+			}
+		}
+		if ob.outsideBands(price) {
+			// 撮合到该档位时会熔断并停止：之后的档位不计入可成交量
+			return false
+		}
+		// 逐笔累计 Maker 的剩余总量（冰山单的隐藏储备同样会刷新成交）；
+		// 启用自成交防护时除 cancel_oldest 外遇到同账户 Maker 时 Taker 会被撤销，视为无法全部成交
+		liquidity, ok := ob.levelLiquidity(&order, node, remaining, &groups)
+		remaining -= liquidity
+		stopped = !ok
+		return ok && remaining > 0
+	})
+	return !stopped && remaining <= 0
+}
+
+// levelLiquidity 返回档位 node 内可供 taker 成交的 Maker 剩余总量（按金额下单时为金额），累计到不少于 need 为止
+// 撮合时会被撤销的订单组兄弟订单不提供流动性，同一 OCO 组只计入最先遇到的一条腿（groups 记录已计入的组）；
+// 遇到同账户 Maker 且 Taker 的 STP 模式不是 cancel_oldest 时 Taker 会被撤销，返回 false
+func (ob *OrderBook) levelLiquidity(taker *Order, node *OrderNode, need int64, groups *[]*orderGroup) (int64, bool) {
+	var total int64
+	for idx := node.Head; idx != NullIndex && total < need; {
+		maker := ob.Arena.Get(idx)
+		idx = maker.Next
+		if maker.siblingDone() || hasGroup(*groups, maker.group) {
+			continue
+		}
+		if isSelfTrade(taker, maker) {
+			if taker.STP != STPCancelOldest {
+				return total, false
 			}
+			continue
 		}
-		
-		noMoreOrders = ob.processLimit(&order, maxNode.Data.(*OrderType).Tree)
-		
-		if maxNode.Data.(*OrderType).Tree.Root == nil {
-			remove(maxNode.Key)
+		if taker.Funds != nil {
+			// 按金额下单：比较对手盘金额
+			total += util.MulDivFloor(node.price, maker.remainingVal(), util.SCALE)
+		} else {
+			total += maker.remainingVal()
+		}
+		if maker.group != nil && maker.group.exits == nil {
+			*groups = append(*groups, maker.group)
 		}
 	}
+	return total, true
 }
 
-func (ob *OrderBook) processLimit(order *Order, tree *binarytree.BinaryTree) bool {
-	orderPrice := order.Price.Float64()
-	var maxNode *binarytree.BinaryNode
-	if order.Type == Sell {
-		maxNode = tree.Max()
-	} else {
-		maxNode = tree.Min()
+// commonProcess 限价单与对手盘 tree 撮合，剩余部分交给 add（挂单或撤销）
+func (ob *OrderBook) commonProcess(order *Order, tree PriceLevels, add func(Order)) {
+	if order.PostOnly == PostOnlyReject || order.PostOnly == PostOnlyReprice {
+		// post-only 订单从不撮合：检查通过（或改价后）直接挂单
+		if ob.checkPostOnly(order, tree) {
+			add(*order)
+		}
+		return
 	}
-	noMoreOrders := false
-	
-	if maxNode == nil {
-		return noMoreOrders
+
+	ob.processLimit(order, tree)
+	if order.Amount.Cmp(decimalZero) == 1 {
+		add(*order)
 	}
-	
-	for maxNode == nil || order.Amount.Cmp(decimalZero) == 1 {
-		if order.Type == Sell {
-			maxNode = tree.Max()
-		} else {
-			maxNode = tree.Min()
-		}
-		
-		if maxNode == nil || noMoreOrders {
-			// 这里不需要处理 break，外层循环会处理
+}
+
+// processLimit 按价格优先逐档撮合，对手盘为空、价格不再交叉或 Taker 被撤销/停止撮合时返回
+func (ob *OrderBook) processLimit(order *Order, tree PriceLevels) bool {
+	orderPrice := order.Price.Val
+	noMoreOrders := false
+
+	for order.Amount.Cmp(decimalZero) == 1 {
+		nodeData := ob.bestNode(tree, order.Type)
+		if nodeData == nil || noMoreOrders {
 			break
 		}
 		if order.Type == Sell {
-			if orderPrice > maxNode.Key {
+			if orderPrice > nodeData.price {
 				noMoreOrders = true
 				return noMoreOrders
 			}
 		} else {
-			if orderPrice < maxNode.Key {
+			if orderPrice < nodeData.price {
 				noMoreOrders = true
 				return noMoreOrders
 			}
 		}
 
-		nodeData := maxNode.Data.(*OrderNode)
 		currIdx := nodeData.Head
 
+		if ob.breachesBand(ob.Arena.Get(currIdx).Price) {
+			// 成交价超出价格带：已自动熔断，停止撮合，剩余部分按有效期挂单或撤销
+			noMoreOrders = true
+			break
+		}
+
+		if ob.policy != nil {
+			// 非 FIFO 策略：整档分配，档位内价格相同只需检查档首订单
+			head := ob.Arena.Get(currIdx)
+			if order.Type == Sell && head.Price.Cmp(order.Price) == -1 || order.Type == Buy && head.Price.Cmp(order.Price) == 1 {
+				noMoreOrders = true
+			} else {
+				noMoreOrders = ob.matchLevel(order, nodeData)
+			}
+			currIdx = NullIndex
+		}
+
 		for currIdx != NullIndex {
 			ele := ob.Arena.Get(currIdx)
 			nextIdx := ele.Next // Save next
@@ -121,6 +208,23 @@ func (ob *OrderBook) processLimit(order *Order, tree *binarytree.BinaryTree) boo
 				}
 			}
 
+			if ele.siblingDone() {
+				// 同组订单已成交或撤销，兄弟订单不再参与撮合
+				ob.cancelMaker(nodeData, currIdx, CancelOrderGroup)
+				currIdx = nextIdx
+				continue
+			}
+
+			if isSelfTrade(order, ele) {
+				// 自成交防护：不成交，按 Taker 的 STP 模式撤单
+				if ob.preventSelfTrade(order, nodeData, currIdx) {
+					noMoreOrders = true
+					break
+				}
+				currIdx = nextIdx
+				continue
+			}
+
 			if ele.Amount.Cmp(order.Amount) == 1 {
 				// Case 1: Maker 量 > Taker 量 (部分成交)
 				// 使用原地修改
@@ -129,7 +233,7 @@ func (ob *OrderBook) processLimit(order *Order, tree *binarytree.BinaryTree) boo
 
 				// 触发成交事件
 				// Maker: ele, Taker: order
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, order.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, order.Amount.Val)
 
 				order.Amount.SetZero() // 优化：原地置零
 				
@@ -139,13 +243,10 @@ func (ob *OrderBook) processLimit(order *Order, tree *binarytree.BinaryTree) boo
 				// Case 2: Maker 量 == Taker 量 (完全成交)
 				
 				// 触发成交事件
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, ele.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, ele.Amount.Val)
 
-				// 先删除 map 索引
-				delete(ob.orders, ele.ID)
-				
-				// 再从链表移除 (会调用 Arena.Free)
-				nodeData.removeOrder(ob.Arena, currIdx)
+				// 移出订单簿（冰山单则刷新后排到队尾）
+				ob.removeFilledMaker(nodeData, currIdx)
 				
 				order.Amount.SetZero()
 				
@@ -155,20 +256,18 @@ func (ob *OrderBook) processLimit(order *Order, tree *binarytree.BinaryTree) boo
 				// Case 3: Maker 量 < Taker 量 (Maker 吃光，Taker 还有剩)
 				
 				// 触发成交事件
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, ele.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, ele.Amount.Val)
 
 				order.Amount.SubMut(ele.Amount)
 				
-				delete(ob.orders, ele.ID)
-				nodeData.removeOrder(ob.Arena, currIdx)
+				// 移出订单簿（冰山单则刷新后排到队尾）
+				ob.removeFilledMaker(nodeData, currIdx)
 			}
 			currIdx = nextIdx
 		}
 
 		if nodeData.Count == 0 {
-			node := tree.Root.Remove(maxNode.Key) 
-			tree.Root = node
-			nodeData.Release() // 回收空的 OrderNode
+			ob.releaseLevel(tree, nodeData) // 回收空的 OrderNode
 		}
 	}
 	return noMoreOrders
@@ -267,7 +267,7 @@ func TestProcessLimitOrder(t *testing.T) {
 		// If tt.partialOrder is not nil, it means the input order was not fully filled.
 		// Check if it exists in the order book.
 		if tt.partialOrder != nil {
-			idx, exists := ob.orders[tt.partialOrder.ID]
+			idx, exists := ob.lookup(tt.partialOrder.ID)
 			if !exists {
 				t.Fatalf("Case %d: Partial order should exist in book", i)
 			}
@@ -288,7 +288,7 @@ func TestProcessLimitOrder(t *testing.T) {
 				// Check if the last trade fully filled the order
 				// Difficult to check without tracking cumulative amount.
 				// But we can check if order ID exists.
-				_, exists := ob.orders[tt.input.ID]
+				_, exists := ob.lookup(tt.input.ID)
 				if exists {
 					// Check amount. If 0, it's a bug (should be deleted).
 					// But `processLimit` deletes it.
@@ -300,3 +300,29 @@ func TestProcessLimitOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessSteadyStateAllocs(t *testing.T) {
+	ob := NewOrderBook(nil)
+	sell := *NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0"))
+	sell2 := *NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("101.0"))
+	buy := *NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("101.0"))
+	rest := *NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("90.0"))
+	hit := *NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("90.0"))
+	cycle := func() {
+		// 挂两档卖单后一笔扫光，再挂买单并被卖单吃掉：档位与订单槽位都回收复用
+		sell.Amount.Val, sell2.Amount.Val, buy.Amount.Val = 2e8, 1e8, 3e8
+		rest.Amount.Val, hit.Amount.Val = 1e8, 1e8
+		ob.Process(sell)
+		ob.Process(sell2)
+		ob.Process(buy)
+		ob.Process(rest)
+		ob.Process(hit)
+	}
+	cycle()
+	if allocs := testing.AllocsPerRun(100, cycle); allocs != 0 {
+		t.Fatalf("steady-state matching should not allocate (have: %v allocs per cycle)", allocs)
+	}
+	if ob.BuyTree.Len() != 0 || ob.SellTree.Len() != 0 {
+		t.Fatal("book should be empty after each cycle")
+	}
+}
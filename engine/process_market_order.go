@@ -1,101 +1,135 @@
 package engine
 
-import (
-	"github.com/goovo/binarytree"
-)
-
 // ProcessMarket 执行市价单撮合流程
 func (ob *OrderBook) ProcessMarket(order Order) {
 	ob.mutex.Lock()
 	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
 
-	if order.Type == Buy {
-		ob.commonProcessMarket(order, ob.SellTree, ob.addBuyOrder, ob.removeSellNode)
+	if order.StopPrice != nil {
+		// 止损单：触发后按市价单处理
+		ob.addStopOrder(order, true)
 	} else {
-		ob.commonProcessMarket(order, ob.BuyTree, ob.addSellOrder, ob.removeBuyNode)
+		ob.processMarket(order)
 	}
+	ob.triggerStops()
 }
 
-func (ob *OrderBook) commonProcessMarket(order Order, tree *binarytree.BinaryTree, add func(Order), remove func(float64) error) {
-	var maxNode *binarytree.BinaryNode
+// processMarket 市价单撮合（调用方持有锁）
+func (ob *OrderBook) processMarket(order Order) {
+	tree := ob.SellTree
 	if order.Type == Sell {
-		maxNode = tree.Max()
-	} else {
-		maxNode = tree.Min()
+		tree = ob.BuyTree
+	}
+
+	order.enter()
+	if ob.rejectDuplicate(&order) || ob.rejectInvalid(&order, true) || ob.rejectNotTrading(&order) {
+		return
+	}
+	if ob.state == StateAuction {
+		ob.rejectOrder(&order, RejectAuction)
+		return
+	}
+
+	ob.setPriceLimit(&order, tree)
+
+	// 市价单本身即为 IOC，FOK 额外要求对手盘深度足够（保护价范围内）
+	if order.TimeInForce == FOK && !ob.canFill(&order, tree, true) {
+		ob.cancelWithReason(&order, CancelFillOrKill)
+		return
+	}
+	if order.Funds != nil {
+		// 按报价币种金额下单
+		ob.processQuote(order, tree)
+		return
 	}
-	if maxNode == nil {
+	ob.commonProcessMarket(&order, tree)
+}
+
+// commonProcessMarket 市价单与对手盘 tree 撮合（IOC），未成交部分撤销
+func (ob *OrderBook) commonProcessMarket(order *Order, tree PriceLevels) {
+	if ob.bestNode(tree, order.Type) == nil {
 		// 市价单如果不匹配，直接丢弃或取消（IOC/FOK）
 		// 这里假设是 IOC (Immediate or Cancel)，未成交部分取消
-		if order.ID != "" {
-			// 触发取消事件（剩余全部取消）
-			ob.listener.OnOrderCancelled(order.ID)
+		if order.ID != "" && order.Funds == nil {
+			// 触发取消事件（剩余全部取消；按金额下单的由 processQuote 处理）
+			ob.cancelWithReason(order, order.remainderReason())
 		}
 		return
 	}
-	count := 0
-	noMoreOrders := false
-	
-	// orderOriginalAmount := order.Amount.Clone()
-
-	for maxNode == nil || order.Amount.Cmp(decimalZero) == 1 {
-		count++
-		if order.Type == Sell {
-			maxNode = tree.Max()
-		} else {
-			maxNode = tree.Min()
-		}
-		if maxNode == nil || noMoreOrders {
-			if order.Amount.Cmp(decimalZero) == 1 {
-				// 市价单未完全成交，剩余部分取消
-				ob.listener.OnOrderCancelled(order.ID)
-			}
-			break
-		}
-
-		noMoreOrders = ob.processLimitMarket(&order, maxNode.Data.(*OrderType).Tree)
 
-		if maxNode.Data.(*OrderType).Tree.Root == nil {
-			remove(maxNode.Key)
-		}
+	ob.processLimitMarket(order, tree)
+	if order.Amount.Cmp(decimalZero) == 1 && order.Funds == nil {
+		// 市价单未完全成交（或触及保护价），剩余部分取消
+		ob.cancelWithReason(order, order.remainderReason())
 	}
 }
 
-func (ob *OrderBook) processLimitMarket(order *Order, tree *binarytree.BinaryTree) bool {
-	var maxNode *binarytree.BinaryNode
-	if order.Type == Sell {
-		maxNode = tree.Max()
-	} else {
-		maxNode = tree.Min()
-	}
+// processLimitMarket 按价格优先逐档扫单，对手盘为空、触及保护价或 Taker 停止撮合时返回
+func (ob *OrderBook) processLimitMarket(order *Order, tree PriceLevels) bool {
 	noMoreOrders := false
-	
-	if maxNode == nil {
-		return noMoreOrders
-	}
-	
-	for maxNode == nil || order.Amount.Cmp(decimalZero) == 1 {
-		if order.Type == Sell {
-			maxNode = tree.Max()
-		} else {
-			maxNode = tree.Min()
-		}
-		if maxNode == nil || noMoreOrders {
+
+	for order.Amount.Cmp(decimalZero) == 1 {
+		nodeData := ob.bestNode(tree, order.Type)
+		if nodeData == nil || noMoreOrders {
 			break
 		}
-		
-		nodeData := maxNode.Data.(*OrderNode)
+
 		currIdx := nodeData.Head
+
+		if order.priceLimit != nil && !order.withinLimit(ob.Arena.Get(currIdx).Price) {
+			// 超出保护价，停止扫单
+			order.cancelReason = CancelPriceProtection
+			noMoreOrders = true
+			break
+		}
+		if ob.breachesBand(ob.Arena.Get(currIdx).Price) {
+			// 成交价超出价格带：已自动熔断，停止扫单
+			order.cancelReason = CancelVolatilityHalt
+			noMoreOrders = true
+			break
+		}
+
+		if ob.policy != nil {
+			// 非 FIFO 策略：整档分配
+			noMoreOrders = ob.matchLevel(order, nodeData)
+			currIdx = NullIndex
+		}
 		
 		for currIdx != NullIndex {
 			ele := ob.Arena.Get(currIdx)
 			nextIdx := ele.Next // Save next
 			
+			if order.Funds != nil && !ob.affordQuote(order, ele.Price) {
+				// 剩余金额不足一个最小数量单位
+				order.cancelReason = CancelInsufficientFunds
+				noMoreOrders = true
+				break
+			}
+
+			if ele.siblingDone() {
+				// 同组订单已成交或撤销，兄弟订单不再参与撮合
+				ob.cancelMaker(nodeData, currIdx, CancelOrderGroup)
+				currIdx = nextIdx
+				continue
+			}
+
+			if isSelfTrade(order, ele) {
+				// 自成交防护：不成交，按 Taker 的 STP 模式撤单
+				if ob.preventSelfTrade(order, nodeData, currIdx) {
+					noMoreOrders = true
+					break
+				}
+				currIdx = nextIdx
+				continue
+			}
+
 			if ele.Amount.Cmp(order.Amount) == 1 {
 				// Case 1: Maker > Taker
 				nodeData.Volume.SubMut(order.Amount)
 				ele.Amount.SubMut(order.Amount)
 
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, order.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, order.Amount.Val)
 
 				order.Amount.SetZero()
 				noMoreOrders = true
@@ -103,33 +137,29 @@ func (ob *OrderBook) processLimitMarket(order *Order, tree *binarytree.BinaryTre
 			}
 			if ele.Amount.Cmp(order.Amount) == 0 {
 				// Case 2: Maker == Taker
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, ele.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, ele.Amount.Val)
 
 				order.Amount.SetZero()
 				
-				delete(ob.orders, ele.ID)
-				
-				nodeData.removeOrder(ob.Arena, currIdx)
+				// 移出订单簿（冰山单则刷新后排到队尾）
+				ob.removeFilledMaker(nodeData, currIdx)
 
 				currIdx = nextIdx
 				break
 			} else {
 				// Case 3: Maker < Taker
-				ob.listener.OnTrade(ele.ID, order.ID, ele.Type, ele.Price.Val, ele.Amount.Val)
+				ob.onTrade(ele, order, ele.Price.Val, ele.Amount.Val)
 
 				order.Amount.SubMut(ele.Amount)
 				
-				delete(ob.orders, ele.ID)
-				
-				nodeData.removeOrder(ob.Arena, currIdx)
+				// 移出订单簿（冰山单则刷新后排到队尾）
+				ob.removeFilledMaker(nodeData, currIdx)
 			}
 			currIdx = nextIdx
 		}
 
 		if nodeData.Count == 0 {
-			node := tree.Root.Remove(maxNode.Key) 
-			tree.Root = node
-			nodeData.Release()
+			ob.releaseLevel(tree, nodeData)
 		}
 	}
 	return noMoreOrders
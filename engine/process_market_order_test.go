@@ -132,7 +132,7 @@ func TestProcessMarketOrder(t *testing.T) {
 		// 我们的 ProcessMarket 逻辑是 IOC：剩余部分取消。
 		// 所以 OrderBook 里不应该有 Taker 的剩余部分。
 		// 验证 Taker 是否在 OrderBook
-		_, exists := ob.orders[tt.input.ID]
+		_, exists := ob.lookup(tt.input.ID)
 		if exists {
 			// 只有当完全没成交且还没取消时才存在？
 			// 不，ProcessMarket 逻辑：如果不匹配，取消。如果匹配部分，剩余取消。
@@ -141,3 +141,24 @@ func TestProcessMarketOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessMarketSteadyStateAllocs(t *testing.T) {
+	ob := NewOrderBook(nil)
+	sell := *NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0"))
+	sell2 := *NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("101.0"))
+	market := *NewOrder("m1", Buy, DecimalBig("1.0"), DecimalBig("0"))
+	cycle := func() {
+		// 市价单扫过两档卖单：档位与订单槽位都回收复用
+		sell.Amount.Val, sell2.Amount.Val, market.Amount.Val = 1e8, 1e8, 2e8
+		ob.Process(sell)
+		ob.Process(sell2)
+		ob.ProcessMarket(market)
+	}
+	cycle()
+	if allocs := testing.AllocsPerRun(100, cycle); allocs != 0 {
+		t.Fatalf("steady-state market orders should not allocate (have: %v allocs per cycle)", allocs)
+	}
+	if ob.SellTree.Len() != 0 {
+		t.Fatal("book should be empty after each cycle")
+	}
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// processQuote 按报价币种金额撮合市价单（调用方持有锁）
+// 每个 Maker 成交前按剩余金额计算可成交数量（向下取整到 LotSize），成交后扣减 price × fill（向上取整），
+// 保证成交总额不超过 Funds；结束时剩余金额通过 DustListener 上报并撤销订单
+func (ob *OrderBook) processQuote(order Order, tree PriceLevels) {
+	order.Funds = order.Funds.Clone()
+	// 数量由剩余金额逐档计算，这里只需保证进入撮合循环
+	order.Amount = &util.StandardBigDecimal{Val: math.MaxInt64}
+
+	ob.commonProcessMarket(&order, tree)
+
+	if order.Funds.Val > 0 {
+		if ob.dustListener != nil {
+			ob.dustListener.OnOrderDust(order.ID, order.Funds.Val)
+		}
+		ob.cancelWithReason(&order, order.remainderReason())
+	}
+}
+
+// affordQuote 按剩余金额与 Maker 价格设置 Taker 本次可成交数量，返回 false 表示金额不足一个最小数量单位
+func (ob *OrderBook) affordQuote(order *Order, price *util.StandardBigDecimal) bool {
+	qty := order.Funds.DivFloor(price)
+	qty.Val -= qty.Val % ob.spec.LotSize.Val
+	order.Amount.Val = qty.Val
+	return qty.Val > 0
+}
+
+// remainderReason 返回市价单剩余部分的撤单原因：未触及保护价、价格带或金额限制时为对手盘不足
+func (order *Order) remainderReason() CancelReason {
+	if order.cancelReason == "" {
+		return CancelNoLiquidity
+	}
+	return order.cancelReason
+}
+
+// spendFunds 成交后扣减按金额下单的剩余金额（普通订单忽略）
+func (order *Order) spendFunds(price, amount *util.StandardBigDecimal) {
+	if order.Funds != nil {
+		order.Funds.SubMut(price.MulCeil(amount))
+	}
+}
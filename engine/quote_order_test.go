@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+type dustListener struct {
+	MockListener
+	Dust map[string]int64
+}
+
+func (l *dustListener) OnOrderDust(id string, funds int64) {
+	if l.Dust == nil {
+		l.Dust = map[string]int64{}
+	}
+	l.Dust[id] = funds
+}
+
+func newQuote(id string, side Side, funds string) Order {
+	order := NewOrder(id, side, DecimalBig("0.0"), DecimalBig("1.0"))
+	order.Funds = DecimalBig(funds)
+	return *order
+}
+
+func TestQuoteMarketBuyAcrossLevels(t *testing.T) {
+	listener := &dustListener{}
+	ob := NewOrderBook(listener)
+	ob.SetLotSize(DecimalBig("0.01"))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("5.0"), DecimalBig("300.0")))
+
+	ob.ProcessMarket(newQuote("q", Buy, "500.0"))
+
+	if len(listener.Trades) != 2 {
+		t.Fatalf("quote order should sweep two levels (have: %+v)", listener.Trades)
+	}
+	// 100 × 1 = 100，剩余 400 / 300 = 1.333... 向下取整到 0.01 → 1.33，花费 399
+	if listener.Trades[1].Amount != DecimalBig("1.33").Val {
+		t.Fatalf("fill should be rounded down to lot size (have: %d)", listener.Trades[1].Amount)
+	}
+	if listener.Dust["q"] != DecimalBig("1.0").Val {
+		t.Fatalf("dust should be reported (have: %d)", listener.Dust["q"])
+	}
+	if len(listener.Cancelled) != 1 || listener.Cancelled[0] != "q" {
+		t.Fatal("quote order with dust should be cancelled")
+	}
+	if s2 := ob.Arena.Get(indexOf(ob, "s2")); s2.Amount.Cmp(DecimalBig("3.67")) != 0 {
+		t.Fatalf("maker should be partially filled (have: %s)", s2.Amount)
+	}
+}
+
+func TestQuoteMarketExactSpend(t *testing.T) {
+	listener := &dustListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("10.0"), DecimalBig("50.0")))
+
+	ob.ProcessMarket(newQuote("q", Buy, "100.0"))
+	if len(listener.Trades) != 1 || listener.Trades[0].Amount != DecimalBig("2.0").Val {
+		t.Fatalf("unexpected trades (have: %+v)", listener.Trades)
+	}
+	if len(listener.Cancelled) != 0 || len(listener.Dust) != 0 {
+		t.Fatal("fully spent quote order should not be cancelled")
+	}
+}
+
+func TestQuoteMarketNeverOverspends(t *testing.T) {
+	listener := &dustListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("3.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("7.0")))
+
+	funds := DecimalBig("10.0")
+	ob.ProcessMarket(newQuote("q", Buy, funds.String()))
+	spent := &util.StandardBigDecimal{}
+	for _, trade := range listener.Trades {
+		spent.AddMut((&util.StandardBigDecimal{Val: trade.Price}).MulCeil(&util.StandardBigDecimal{Val: trade.Amount}))
+	}
+	if spent.Cmp(funds) == 1 || spent.Val+listener.Dust["q"] != funds.Val {
+		t.Fatalf("spent %s of %s (dust: %d)", spent, funds, listener.Dust["q"])
+	}
+}
+
+func TestQuoteMarketEmptyBook(t *testing.T) {
+	listener := &dustListener{}
+	ob := NewOrderBook(listener)
+	ob.ProcessMarket(newQuote("q", Buy, "100.0"))
+	if len(listener.Cancelled) != 1 || listener.Dust["q"] != DecimalBig("100.0").Val {
+		t.Fatal("unfilled quote order should be cancelled with all funds reported")
+	}
+}
+
+func TestQuoteOrderJSON(t *testing.T) {
+	var order Order
+	if err := order.FromJSON([]byte(`{"id":"q","type":"buy","price":"1.0","funds":"500.0"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if order.Funds.Cmp(DecimalBig("500.0")) != 0 || order.Amount.Val != 0 {
+		t.Fatalf("unexpected order (have: %+v)", order)
+	}
+	if err := order.FromJSON([]byte(`{"id":"q","type":"buy","price":"1.0","funds":"0"}`)); err == nil {
+		t.Fatal("zero funds should be rejected")
+	}
+}
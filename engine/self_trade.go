@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// STPMode 自成交防护模式，Taker 与 Maker 属于同一账户时按 Taker 的模式处理
+type STPMode string
+
+// STPCancelNewest 撤销 Taker 剩余部分（默认）；STPCancelOldest 撤销 Maker 后继续撮合；
+// STPCancelBoth 同时撤销双方；STPDecrementAndCancel 双方各减去较小数量，减为零的一方撤销
+const (
+	STPCancelNewest       STPMode = "cancel_newest"
+	STPCancelOldest       STPMode = "cancel_oldest"
+	STPCancelBoth         STPMode = "cancel_both"
+	STPDecrementAndCancel STPMode = "decrement_and_cancel"
+)
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (m STPMode) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON 实现 JSON 反序列化接口（空字符串视为 cancel_newest）
+func (m *STPMode) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `""`, `"cancel_newest"`:
+		*m = STPCancelNewest
+	case `"cancel_oldest"`:
+		*m = STPCancelOldest
+	case `"cancel_both"`:
+		*m = STPCancelBoth
+	case `"decrement_and_cancel"`:
+		*m = STPDecrementAndCancel
+	default:
+		return &json.UnsupportedValueError{
+			Value: reflect.New(reflect.TypeOf(data)),
+			Str:   string(data),
+		}
+	}
+
+	return nil
+}
+
+// String 实现 Stringer 接口（未设置时返回 cancel_newest）
+func (m STPMode) String() string {
+	switch m {
+	case STPCancelOldest:
+		return "cancel_oldest"
+	case STPCancelBoth:
+		return "cancel_both"
+	case STPDecrementAndCancel:
+		return "decrement_and_cancel"
+	}
+	return "cancel_newest"
+}
+
+// isSelfTrade 判断 Taker 与 Maker 是否属于同一账户（未设置账户的订单不做防护）
+func isSelfTrade(taker, maker *Order) bool {
+	return taker.Account != "" && taker.Account == maker.Account
+}
+
+// preventSelfTrade 按 Taker 的 STP 模式处理一次自成交，不产生成交
+// 返回 true 表示 Taker 已撤销或数量已为零，应停止撮合
+func (ob *OrderBook) preventSelfTrade(order *Order, node *OrderNode, idx IndexType) bool {
+	maker := ob.Arena.Get(idx)
+	makerID := maker.ID
+	if ob.stpListener != nil {
+		ob.stpListener.OnSelfTradePrevented(makerID, order.ID, order.STP)
+	}
+
+	cancelMaker, cancelTaker := false, false
+	switch order.STP {
+	case STPCancelOldest:
+		cancelMaker = true
+	case STPCancelBoth:
+		cancelMaker, cancelTaker = true, true
+	case STPDecrementAndCancel:
+		remaining := maker.remaining()
+		switch remaining.Cmp(order.Amount) {
+		case 1:
+			ob.reduceOrder(maker, order.Amount)
+			cancelTaker = true
+		case 0:
+			cancelMaker, cancelTaker = true, true
+		default:
+			order.Amount.SubMut(remaining)
+			order.spendFunds(maker.Price, remaining)
+			cancelMaker = true
+		}
+	default:
+		cancelTaker = true
+	}
+
+	if cancelMaker {
+		ob.cancelMaker(node, idx, CancelSelfTrade)
+	}
+	if cancelTaker {
+		order.Amount.SetZero()
+		if order.Funds != nil {
+			// 按金额下单的 Taker 已撤销，不再上报剩余金额
+			order.Funds.SetZero()
+		}
+		ob.cancelWithReason(order, CancelSelfTrade)
+	}
+	return cancelTaker
+}
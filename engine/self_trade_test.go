@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+type stpListener struct {
+	MockListener
+	Prevented []string
+}
+
+func (l *stpListener) OnSelfTradePrevented(makerID, takerID string, mode STPMode) {
+	l.Prevented = append(l.Prevented, makerID+"/"+takerID)
+}
+
+func newAccountOrder(id string, side Side, amount, price, account string, mode STPMode) Order {
+	order := NewOrder(id, side, DecimalBig(amount), DecimalBig(price))
+	order.Account = account
+	order.STP = mode
+	return *order
+}
+
+func TestSelfTradePrevention(t *testing.T) {
+	var tests = []struct {
+		mode      STPMode
+		taker     string
+		trades    []string
+		cancelled []string
+		book      map[string]string // 剩余挂单 ID -> 数量
+	}{
+		// 卖方挂单：m1 (A, 2.0)、m2 (B, 2.0)，买方 A 吃单
+		{STPCancelNewest, "3.0", nil, []string{"t"}, map[string]string{"m1": "2.0", "m2": "2.0"}},
+		{STPCancelOldest, "1.0", []string{"m2"}, []string{"m1"}, map[string]string{"m2": "1.0"}},
+		{STPCancelBoth, "3.0", nil, []string{"m1", "t"}, map[string]string{"m2": "2.0"}},
+		{STPDecrementAndCancel, "3.0", []string{"m2"}, []string{"m1"}, map[string]string{"m2": "1.0"}},
+		{STPDecrementAndCancel, "1.5", nil, []string{"t"}, map[string]string{"m1": "0.5", "m2": "2.0"}},
+		{STPDecrementAndCancel, "2.0", nil, []string{"m1", "t"}, map[string]string{"m2": "2.0"}},
+	}
+
+	for _, tt := range tests {
+		for _, market := range []bool{false, true} {
+			listener := &stpListener{}
+			ob := NewOrderBook(listener)
+			ob.Process(newAccountOrder("m1", Sell, "2.0", "100.0", "A", ""))
+			ob.Process(newAccountOrder("m2", Sell, "2.0", "100.0", "B", ""))
+
+			taker := newAccountOrder("t", Buy, tt.taker, "100.0", "A", tt.mode)
+			if market {
+				ob.ProcessMarket(taker)
+			} else {
+				taker.TimeInForce = IOC
+				ob.Process(taker)
+			}
+
+			if len(listener.Prevented) != 1 || listener.Prevented[0] != "m1/t" {
+				t.Fatalf("%s: self trade should be reported (have: %v)", tt.mode, listener.Prevented)
+			}
+			var makers []string
+			for _, trade := range listener.Trades {
+				makers = append(makers, trade.MakerID)
+			}
+			if !reflect.DeepEqual(makers, tt.trades) {
+				t.Fatalf("%s: unexpected trades (have: %v, want: %v)", tt.mode, makers, tt.trades)
+			}
+			if !reflect.DeepEqual(listener.Cancelled, tt.cancelled) {
+				t.Fatalf("%s: unexpected cancels (have: %v, want: %v)", tt.mode, listener.Cancelled, tt.cancelled)
+			}
+			book := map[string]string{}
+			for _, idx := range ob.orders.index {
+				if idx != NullIndex {
+					order := ob.Arena.Get(idx)
+					book[order.ID] = order.remaining().String()
+				}
+			}
+			for id, amount := range tt.book {
+				if book[id] != DecimalBig(amount).String() {
+					t.Fatalf("%s: unexpected book (have: %v, want: %v)", tt.mode, book, tt.book)
+				}
+			}
+			if len(book) != len(tt.book) {
+				t.Fatalf("%s: unexpected book (have: %v, want: %v)", tt.mode, book, tt.book)
+			}
+		}
+	}
+}
+
+func TestSelfTradeDifferentAccountsMatch(t *testing.T) {
+	listener := &stpListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newAccountOrder("m1", Sell, "1.0", "100.0", "A", ""))
+	ob.Process(newAccountOrder("t1", Buy, "1.0", "100.0", "B", ""))
+	ob.Process(*NewOrder("m2", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("t2", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if len(listener.Trades) != 2 || len(listener.Prevented) != 0 {
+		t.Fatal("orders of different or empty accounts should match")
+	}
+}
+
+func TestSelfTradeFOK(t *testing.T) {
+	listener := &stpListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(newAccountOrder("m1", Sell, "2.0", "100.0", "A", ""))
+	ob.Process(newAccountOrder("m2", Sell, "2.0", "100.0", "B", ""))
+
+	taker := newAccountOrder("t", Buy, "3.0", "100.0", "A", STPCancelOldest)
+	taker.TimeInForce = FOK
+	ob.Process(taker)
+	if len(listener.Trades) != 0 || len(listener.Prevented) != 0 {
+		t.Fatal("own liquidity should not count towards FOK")
+	}
+
+	taker = newAccountOrder("t", Buy, "2.0", "100.0", "A", STPCancelOldest)
+	taker.TimeInForce = FOK
+	ob.Process(taker)
+	if len(listener.Trades) != 1 || len(listener.Prevented) != 1 {
+		t.Fatalf("FOK should fill against other accounts (have: %+v)", listener.Trades)
+	}
+}
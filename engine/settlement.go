@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// Resolution 二元市场的结算结果
+type Resolution string
+
+// ResolutionYes 每份 YES 兑付 1；ResolutionNo 每份 NO 兑付 1；ResolutionInvalid 无效市场，每份 YES、NO 各兑付 0.5
+const (
+	ResolutionYes     Resolution = "yes"
+	ResolutionNo      Resolution = "no"
+	ResolutionInvalid Resolution = "invalid"
+)
+
+// AccountSettlement 账户在二元市场中的成交持仓与结算兑付（定点数，Scale=1e8）
+type AccountSettlement struct {
+	Yes    int64 // 净买入的 YES 份额（负数表示净卖出）
+	No     int64 // 净买入的 NO 份额（负数表示净卖出）
+	Cost   int64 // 净支出：买入支付减卖出收入
+	Payout int64 // 按结算结果兑付的金额（结算前为 0）
+}
+
+// SettlementReport 结算报告：各账户按成交持仓计算的兑付金额
+type SettlementReport struct {
+	Resolution Resolution
+	Accounts   map[string]AccountSettlement
+}
+
+// Position 返回账户当前的成交持仓（只统计带账户 ID 的订单）
+func (m *BinaryMarket) Position(account string) AccountSettlement {
+	m.ledgerMu.Lock()
+	defer m.ledgerMu.Unlock()
+	if position, ok := m.positions[account]; ok {
+		return *position
+	}
+	return AccountSettlement{}
+}
+
+// Resolve 按结算结果结束市场：撤销两个订单簿的全部挂单与条件单（CancelMarketResolved），
+// 订单簿进入 StateResolved 不再接受新订单，并按各账户的成交持仓生成结算报告
+func (m *BinaryMarket) Resolve(resolution Resolution) (*SettlementReport, error) {
+	switch resolution {
+	case ResolutionYes, ResolutionNo, ResolutionInvalid:
+	default:
+		return nil, errors.New("invalid resolution")
+	}
+
+	m.Yes.mutex.Lock()
+	defer m.Yes.mutex.Unlock()
+	m.No.mutex.Lock()
+	defer m.No.mutex.Unlock()
+	defer m.No.releaseIDs()
+	defer m.Yes.releaseIDs()
+	return m.resolve(resolution)
+}
+
+// resolve 结算市场（调用方已检查 resolution 并持有两个订单簿的锁）
+func (m *BinaryMarket) resolve(resolution Resolution) (*SettlementReport, error) {
+	if m.Yes.state == StateResolved {
+		return nil, errors.New("market already resolved")
+	}
+	for _, ob := range []*OrderBook{m.Yes, m.No} {
+		ob.massCancel(MassCancelFilter{}, CancelMarketResolved)
+		ob.setState(StateResolved, StateChangeResolution)
+	}
+
+	m.ledgerMu.Lock()
+	defer m.ledgerMu.Unlock()
+	report := &SettlementReport{Resolution: resolution, Accounts: make(map[string]AccountSettlement, len(m.positions))}
+	for account, position := range m.positions {
+		switch resolution {
+		case ResolutionYes:
+			position.Payout = position.Yes
+		case ResolutionNo:
+			position.Payout = position.No
+		default:
+			position.Payout = (position.Yes + position.No) / 2
+		}
+		report.Accounts[account] = *position
+	}
+	return report, nil
+}
+
+// recordTrade 记录结果代币 outcome 上的直接成交（side 为 Maker 方向）
+// 按订单携带的账户记账，直接提交到 Yes / No 订单簿的订单同样计入持仓
+func (m *BinaryMarket) recordTrade(outcome Outcome, makerAccount, takerAccount string, side Side, price, amount int64) {
+	m.ledgerMu.Lock()
+	defer m.ledgerMu.Unlock()
+	m.recordFill(makerAccount, outcome, side, price, amount)
+	m.recordFill(takerAccount, outcome, oppositeSide(side), price, amount)
+}
+
+// recordFill 按成交更新账户的持仓与净支出，account 为空时不记账（调用方持有 ledgerMu）
+func (m *BinaryMarket) recordFill(account string, outcome Outcome, side Side, price, amount int64) {
+	if account == "" {
+		return
+	}
+	position, ok := m.positions[account]
+	if !ok {
+		position = &AccountSettlement{}
+		m.positions[account] = position
+	}
+
+	shares, cost := amount, util.MulDivFloor(price, amount, util.SCALE)
+	if side == Sell {
+		shares, cost = -shares, -cost
+	}
+	if outcome == OutcomeYes {
+		position.Yes += shares
+	} else {
+		position.No += shares
+	}
+	position.Cost += cost
+}
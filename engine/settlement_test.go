@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"testing"
+)
+
+func newBinaryOrder(id, account string, side Side, amount, price string) Order {
+	order := NewOrder(id, side, DecimalBig(amount), DecimalBig(price))
+	order.Account = account
+	return *order
+}
+
+func TestResolveSettlement(t *testing.T) {
+	listener := &binaryListener{}
+	m := NewBinaryMarket(listener)
+
+	// alice 与 bob 互补铸造 10 份；carol 以 0.7 向 alice 买入 4 份 YES
+	m.Process(OutcomeNo, newBinaryOrder("bob1", "bob", Buy, "10.0", "0.4"))
+	m.Process(OutcomeYes, newBinaryOrder("alice1", "alice", Buy, "10.0", "0.6"))
+	m.Process(OutcomeYes, newBinaryOrder("alice2", "alice", Sell, "4.0", "0.7"))
+	m.Process(OutcomeYes, newBinaryOrder("carol1", "carol", Buy, "4.0", "0.7"))
+	m.Process(OutcomeYes, newBinaryOrder("carol2", "carol", Buy, "1.0", "0.5"))
+	m.Process(OutcomeNo, newBinaryOrder("bob2", "bob", Sell, "1.0", "0.9"))
+
+	alice := m.Position("alice")
+	if alice.Yes != DecimalBig("6.0").Val || alice.Cost != DecimalBig("3.2").Val {
+		t.Fatalf("unexpected alice position %+v", alice)
+	}
+
+	report, err := m.Resolve(ResolutionYes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Yes.orders.size() != 0 || m.No.orders.size() != 0 {
+		t.Fatal("resolution should cancel every resting order")
+	}
+	if report.Accounts["alice"].Payout != DecimalBig("6.0").Val || report.Accounts["carol"].Payout != DecimalBig("4.0").Val || report.Accounts["bob"].Payout != 0 {
+		t.Fatalf("unexpected payouts %+v", report.Accounts)
+	}
+	if report.Accounts["bob"].No != DecimalBig("10.0").Val || report.Accounts["bob"].Cost != DecimalBig("4.0").Val {
+		t.Fatalf("unexpected bob position %+v", report.Accounts["bob"])
+	}
+
+	m.Process(OutcomeYes, newBinaryOrder("late", "carol", Buy, "1.0", "0.5"))
+	if listener.Rejected["late"] != RejectResolved {
+		t.Fatal("resolved market should reject new orders")
+	}
+	if _, err := m.Resolve(ResolutionNo); err == nil {
+		t.Fatal("market should only resolve once")
+	}
+	if m.Yes.SetTradingState(StateContinuous) == nil {
+		t.Fatal("resolved book should not reopen")
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	m := NewBinaryMarket(&binaryListener{})
+	m.Process(OutcomeNo, newBinaryOrder("n", "bob", Buy, "3.0", "0.5"))
+	m.Process(OutcomeYes, newBinaryOrder("y", "alice", Buy, "3.0", "0.5"))
+
+	if _, err := m.Resolve("maybe"); err == nil {
+		t.Fatal("unknown resolution should fail")
+	}
+	report, _ := m.Resolve(ResolutionInvalid)
+	if report.Accounts["alice"].Payout != DecimalBig("1.5").Val || report.Accounts["bob"].Payout != DecimalBig("1.5").Val {
+		t.Fatalf("invalid market should pay 0.5 per share (have: %+v)", report.Accounts)
+	}
+}
+
+func TestSettlementCountsDirectBookFills(t *testing.T) {
+	m := NewBinaryMarket(&binaryListener{})
+	m.Yes.Process(newBinaryOrder("s1", "alice", Sell, "2.0", "0.4"))
+	m.Yes.Process(newBinaryOrder("b1", "bob", Buy, "2.0", "0.4"))
+
+	report, err := m.Resolve(ResolutionYes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Accounts["bob"].Payout != DecimalBig("2.0").Val || report.Accounts["alice"].Yes != -DecimalBig("2.0").Val {
+		t.Fatalf("fills on the YES book should be settled (have: %+v)", report.Accounts)
+	}
+}
@@ -0,0 +1,155 @@
+package engine
+
+// stopOrder 条件单，触发前不进入 BuyTree/SellTree
+type stopOrder struct {
+	order  Order
+	market bool   // 触发后按市价单处理，否则按限价单处理
+	seq    uint64 // 到达顺序
+}
+
+// stopBook 条件单簿
+// 买方向按触发价升序、卖方向按触发价降序排列，同价按到达顺序排列
+type stopBook struct {
+	buys  []*stopOrder
+	sells []*stopOrder
+	index map[uint64]*stopOrder // 引擎订单 ID -> 条件单
+	seq   uint64
+}
+
+func newStopBook() *stopBook {
+	return &stopBook{index: make(map[uint64]*stopOrder)}
+}
+
+// triggered 判断条件单在最新成交价 lastPrice 下是否触发
+// 买入条件单在成交价 >= 触发价时触发，卖出条件单在成交价 <= 触发价时触发
+func (s *stopOrder) triggered(lastPrice int64) bool {
+	if s.order.Type == Buy {
+		return lastPrice >= s.order.StopPrice.Val
+	}
+	return lastPrice <= s.order.StopPrice.Val
+}
+
+// add 按触发优先级插入条件单
+func (sb *stopBook) add(order Order, market bool) {
+	sb.seq++
+	stop := &stopOrder{order: order, market: market, seq: sb.seq}
+	sb.index[order.EID] = stop
+
+	queue := &sb.buys
+	if order.Type == Sell {
+		queue = &sb.sells
+	}
+	pos := len(*queue)
+	for i, s := range *queue {
+		if order.Type == Buy && s.order.StopPrice.Cmp(order.StopPrice) == 1 ||
+			order.Type == Sell && s.order.StopPrice.Cmp(order.StopPrice) == -1 {
+			pos = i
+			break
+		}
+	}
+	*queue = append(*queue, nil)
+	copy((*queue)[pos+1:], (*queue)[pos:])
+	(*queue)[pos] = stop
+}
+
+// next 取出下一笔已触发的条件单，没有则返回 nil
+// 多笔同时满足触发条件时按到达顺序返回，保证级联触发的处理顺序确定
+func (sb *stopBook) next(lastPrice int64) *stopOrder {
+	if lastPrice == 0 || len(sb.index) == 0 {
+		return nil
+	}
+	var best *stopOrder
+	for _, queue := range [][]*stopOrder{sb.buys, sb.sells} {
+		for _, s := range queue {
+			if !s.triggered(lastPrice) {
+				break
+			}
+			if best == nil || s.seq < best.seq {
+				best = s
+			}
+		}
+	}
+	if best != nil {
+		sb.remove(best)
+	}
+	return best
+}
+
+// cancel 撤销引擎订单 ID 为 eid 的条件单，返回被撤销的订单
+func (sb *stopBook) cancel(eid uint64) *Order {
+	stop, ok := sb.index[eid]
+	if !ok {
+		return nil
+	}
+	sb.remove(stop)
+	return &stop.order
+}
+
+func (sb *stopBook) remove(stop *stopOrder) {
+	delete(sb.index, stop.order.EID)
+	queue := &sb.buys
+	if stop.order.Type == Sell {
+		queue = &sb.sells
+	}
+	for i, s := range *queue {
+		if s == stop {
+			*queue = append((*queue)[:i], (*queue)[i+1:]...)
+			return
+		}
+	}
+}
+
+// stop 返回字符串订单 ID 对应的未触发条件单（调用方持有锁）
+func (ob *OrderBook) stop(id string) (*stopOrder, bool) {
+	eid, ok := ob.ids.lookup(id)
+	if !ok {
+		return nil, false
+	}
+	stop, ok := ob.stops.index[eid]
+	return stop, ok
+}
+
+// addStopOrder 将条件单放入条件单簿，等待最新成交价穿越触发价
+func (ob *OrderBook) addStopOrder(order Order, market bool) {
+	order.enter()
+	if ob.rejectDuplicate(&order) || ob.rejectInvalid(&order, market) || ob.rejectNotTrading(&order) {
+		return
+	}
+	ob.stops.add(order, market)
+	if ob.stopListener != nil {
+		ob.stopListener.OnStopAccepted(order.ID)
+	}
+	ob.emit(EventStopAccepted, &order, "")
+}
+
+// triggerStops 按最新成交价触发条件单，并在同一临界区内注入撮合（调用方持有锁）
+// 每处理一笔后重新检查，由其成交引发的级联触发按同样规则继续处理
+func (ob *OrderBook) triggerStops() {
+	for {
+		// 先撤销已触发订单组的兄弟订单，避免其条件单被继续触发
+		ob.settleGroups()
+		if ob.state != StateContinuous {
+			// 熔断、竞价或休市期间条件单不触发
+			return
+		}
+		stop := ob.stops.next(ob.lastPrice)
+		if stop == nil {
+			return
+		}
+		ob.retire(stop.order.EID)
+		if ob.stopListener != nil {
+			ob.stopListener.OnStopTriggered(stop.order.ID, ob.lastPrice)
+		}
+		ob.emit(EventStopTriggered, &stop.order, "")
+		if len(ob.groups.byOrder) > 0 {
+			// OCO 中的条件单被触发即视为执行，撤销其余腿
+			ob.groups.fire(&stop.order)
+			ob.settleGroups()
+		}
+		if stop.market {
+			ob.processMarket(stop.order)
+		} else {
+			ob.process(stop.order)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"testing"
+)
+
+type stopListener struct {
+	MockListener
+	StopAccepted  []string
+	StopTriggered []string
+}
+
+func (l *stopListener) OnStopAccepted(id string) {
+	l.StopAccepted = append(l.StopAccepted, id)
+}
+
+func (l *stopListener) OnStopTriggered(id string, lastPrice int64) {
+	l.StopTriggered = append(l.StopTriggered, id)
+}
+
+func newStop(id string, side Side, amount, price, stop string) Order {
+	order := NewOrder(id, side, DecimalBig(amount), DecimalBig(price))
+	order.StopPrice = DecimalBig(stop)
+	return *order
+}
+
+func TestStopMarketTriggeredByLastTrade(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("105.0")))
+
+	ob.ProcessMarket(newStop("stop", Buy, "1.0", "0.0", "101.0"))
+	if len(listener.StopAccepted) != 1 || len(listener.Trades) != 0 {
+		t.Fatal("stop order should wait outside the book")
+	}
+	if _, ok := ob.lookup("stop"); ok {
+		t.Fatal("stop order should not be in BuyTree")
+	}
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.StopTriggered) != 0 {
+		t.Fatal("stop should not trigger below its stop price")
+	}
+
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("101.0")))
+	if len(listener.StopTriggered) != 1 || len(listener.Trades) != 3 {
+		t.Fatalf("stop should trigger at 101 (triggered: %v, trades: %d)", listener.StopTriggered, len(listener.Trades))
+	}
+	last := listener.Trades[2]
+	if last.MakerID != "s3" || last.TakerID != "stop" || last.Price != DecimalBig("105.0").Val {
+		t.Fatalf("triggered stop should execute as market order (have: %+v)", last)
+	}
+}
+
+func TestStopLimitRestsWhenNotMarketable(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(newStop("stop", Sell, "2.0", "98.0", "100.0"))
+
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if len(listener.StopTriggered) != 1 {
+		t.Fatal("sell stop should trigger when last trade <= stop price")
+	}
+	idx, ok := ob.lookup("stop")
+	if !ok || ob.Arena.Get(idx).Price.Cmp(DecimalBig("98.0")) != 0 {
+		t.Fatal("triggered stop-limit should rest at its limit price")
+	}
+}
+
+func TestStopCascadeIsDeterministic(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("102.0")))
+	ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("104.0")))
+	ob.Process(*NewOrder("s4", Sell, DecimalBig("1.0"), DecimalBig("106.0")))
+
+	// stop-b 触发价更低但到达更晚；stop-c 只能由 stop-a/stop-b 的成交级联触发
+	ob.ProcessMarket(newStop("stop-a", Buy, "1.0", "0.0", "100.0"))
+	ob.ProcessMarket(newStop("stop-b", Buy, "1.0", "0.0", "99.0"))
+	ob.ProcessMarket(newStop("stop-c", Buy, "1.0", "0.0", "104.0"))
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	want := []string{"stop-a", "stop-b", "stop-c"}
+	if len(listener.StopTriggered) != len(want) {
+		t.Fatalf("cascade should trigger all stops (have: %v)", listener.StopTriggered)
+	}
+	for i := range want {
+		if listener.StopTriggered[i] != want[i] {
+			t.Fatalf("trigger order (have: %v, want: %v)", listener.StopTriggered, want)
+		}
+	}
+	if len(ob.GetOrders(0).Sells) != 0 {
+		t.Fatal("cascade should sweep all sells")
+	}
+}
+
+func TestCancelStopOrder(t *testing.T) {
+	listener := &stopListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("2.0"), DecimalBig("100.0")))
+	ob.ProcessMarket(newStop("stop", Buy, "1.0", "0.0", "100.0"))
+
+	order := ob.CancelOrder("stop")
+	if order == nil || order.ID != "stop" || order.Amount.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatalf("stop order should be cancelled (have: %v)", order)
+	}
+	if ob.CancelOrder("stop") != nil {
+		t.Fatal("stop order should be removed from stop book")
+	}
+
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if len(listener.StopTriggered) != 0 {
+		t.Fatal("cancelled stop should not trigger")
+	}
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// TimeInForce 订单有效期类型
+type TimeInForce string
+
+// GTC 一直有效直到撤单（默认）；IOC 立即成交剩余撤销；FOK 全部成交否则全部撤销
+const (
+	GTC TimeInForce = "GTC"
+	IOC TimeInForce = "IOC"
+	FOK TimeInForce = "FOK"
+)
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (tif TimeInForce) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + tif.String() + `"`), nil
+}
+
+// UnmarshalJSON 实现 JSON 反序列化接口（空字符串视为 GTC）
+func (tif *TimeInForce) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `""`, `"GTC"`:
+		*tif = GTC
+	case `"IOC"`:
+		*tif = IOC
+	case `"FOK"`:
+		*tif = FOK
+	default:
+		return &json.UnsupportedValueError{
+			Value: reflect.New(reflect.TypeOf(data)),
+			Str:   string(data),
+		}
+	}
+
+	return nil
+}
+
+// String 实现 Stringer 接口（未设置时返回 GTC）
+func (tif TimeInForce) String() string {
+	switch tif {
+	case IOC:
+		return "IOC"
+	case FOK:
+		return "FOK"
+	}
+	return "GTC"
+}
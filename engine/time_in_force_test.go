@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTimeInForceUnmarshal(t *testing.T) {
+	var tests = []struct {
+		input  string
+		output TimeInForce
+		err    bool
+	}{
+		{`{"tif":"GTC"}`, GTC, false},
+		{`{"tif":"IOC"}`, IOC, false},
+		{`{"tif":"FOK"}`, FOK, false},
+		{`{"tif":""}`, GTC, false},
+		{`{"tif":"DAY"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			obj := struct {
+				TIF TimeInForce `json:"tif"`
+			}{}
+			err := json.Unmarshal([]byte(tt.input), &obj)
+			if tt.err {
+				if err == nil {
+					t.Fatal("invalid time in force should be rejected")
+				}
+				return
+			}
+			if err != nil || obj.TIF != tt.output {
+				t.Fatalf("have: %v %v, want: %v", obj.TIF, err, tt.output)
+			}
+		})
+	}
+}
+
+func TestProcessTimeInForce(t *testing.T) {
+	var tests = []struct {
+		tif       TimeInForce
+		amount    string
+		trades    int
+		cancelled bool
+		rest      string
+	}{
+		// 对手盘共 3.0（7000 与 7100 各 1.5），限价 7100 买入
+		{GTC, "4.0", 2, false, "1"},
+		{IOC, "4.0", 2, true, ""},
+		{IOC, "2.0", 2, false, ""},
+		{FOK, "4.0", 0, true, ""},
+		{FOK, "3.0", 2, false, ""},
+	}
+
+	for i, tt := range tests {
+		listener := &MockListener{}
+		ob := NewOrderBook(listener)
+		ob.Process(*NewOrder("s1", Sell, DecimalBig("1.5"), DecimalBig("7000.0")))
+		ob.Process(*NewOrder("s2", Sell, DecimalBig("1.5"), DecimalBig("7100.0")))
+		ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("7200.0")))
+		listener.Accepted = nil
+
+		order := NewOrder("b1", Buy, DecimalBig(tt.amount), DecimalBig("7100.0"))
+		order.TimeInForce = tt.tif
+		ob.Process(*order)
+
+		if len(listener.Trades) != tt.trades {
+			t.Fatalf("Case %d: trade count (have: %d, want: %d)", i, len(listener.Trades), tt.trades)
+		}
+		cancelled := len(listener.Cancelled) == 1 && listener.Cancelled[0] == "b1"
+		if cancelled != tt.cancelled {
+			t.Fatalf("Case %d: cancelled (have: %v, want: %v)", i, cancelled, tt.cancelled)
+		}
+		idx, ok := ob.lookup("b1")
+		if tt.rest == "" {
+			if ok {
+				t.Fatalf("Case %d: %s order should not rest in book", i, tt.tif)
+			}
+			continue
+		}
+		if !ok || ob.Arena.Get(idx).Amount.Cmp(DecimalBig(tt.rest)) != 0 {
+			t.Fatalf("Case %d: remainder %s should rest in book", i, tt.rest)
+		}
+	}
+}
+
+func TestProcessMarketFOK(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("7000.0")))
+
+	order := NewOrder("s1", Sell, DecimalBig("2.0"), decimalZero)
+	order.TimeInForce = FOK
+	ob.ProcessMarket(*order)
+
+	if len(listener.Trades) != 0 || len(listener.Cancelled) != 1 {
+		t.Fatalf("FOK market order should be cancelled without trades (trades: %d)", len(listener.Trades))
+	}
+	if _, ok := ob.lookup("b1"); !ok {
+		t.Fatal("maker should stay in book")
+	}
+}
@@ -1,22 +1,156 @@
 package engine
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"time"
 
-// Trade describes the trade structure
+	"github.com/goovo/matching-engine/util"
+)
+
+// Trade 成交记录：订单簿成交事件的规范结构
+// Seq 为订单簿事件序号，ID 为成交 ID（订单簿内唯一），Side 为 Maker 方向（与 OnTrade 一致），
+// Price 为成交所在订单簿的成交价；互补撮合（mint / merge）时双方方向相同，BuyOrderID / SellOrderID 为空
 type Trade struct {
-	BuyOrderID  string  `json:"buy_order_id"`
-	SellOrderID string  `json:"sell_order_id"`
-	Amount      float64 `json:"amount"`
-	Price       float64 `json:"price"`
+	Seq          uint64
+	ID           string
+	Time         time.Time
+	MakerOrderID string
+	TakerOrderID string
+	// 双方的引擎订单 ID，仅在订单挂单或未触发期间唯一（不序列化）
+	MakerEngineID uint64
+	TakerEngineID uint64
+	BuyOrderID    string
+	SellOrderID   string
+	Side          Side
+	Amount        *util.StandardBigDecimal
+	Price         *util.StandardBigDecimal
+	Match         MatchType  // 互补撮合方式，普通成交为空
+	Fees          *TradeFees // 未设置手续费表时为 nil
+}
+
+// newTrade 返回 Maker 与 Taker 之间一笔成交的记录（不含序号、ID 与时间）
+func newTrade(maker, taker *Order, price, amount int64) *Trade {
+	trade := &Trade{
+		MakerOrderID:  maker.ID,
+		TakerOrderID:  taker.ID,
+		MakerEngineID: maker.EID,
+		TakerEngineID: taker.EID,
+		Side:          maker.Type,
+		Amount:        &util.StandardBigDecimal{Val: amount},
+		Price:         &util.StandardBigDecimal{Val: price},
+	}
+	switch {
+	case maker.Type == taker.Type:
+	case maker.Type == Buy:
+		trade.BuyOrderID, trade.SellOrderID = maker.ID, taker.ID
+	default:
+		trade.BuyOrderID, trade.SellOrderID = taker.ID, maker.ID
+	}
+	return trade
+}
+
+// tradeJSON Trade 的 JSON 结构：数量、价格与手续费以十进制字符串表示
+type tradeJSON struct {
+	Seq          uint64    `json:"seq"`
+	ID           string    `json:"id"`
+	Time         time.Time `json:"time"`
+	MakerOrderID string    `json:"maker_order_id"`
+	TakerOrderID string    `json:"taker_order_id"`
+	BuyOrderID   string    `json:"buy_order_id,omitempty"`
+	SellOrderID  string    `json:"sell_order_id,omitempty"`
+	Side         Side      `json:"side"`
+	Amount       string    `json:"amount"`
+	Price        string    `json:"price"`
+	Match        MatchType `json:"match,omitempty"`
+
+	MakerFee         string `json:"maker_fee,omitempty"`
+	MakerFeeCurrency string `json:"maker_fee_currency,omitempty"`
+	TakerFee         string `json:"taker_fee,omitempty"`
+	TakerFeeCurrency string `json:"taker_fee_currency,omitempty"`
 }
 
-// FromJSON create the Trade struct from json string
+// FromJSON 从 JSON 字符串创建 Trade 结构体
 func (trade *Trade) FromJSON(msg []byte) error {
 	return json.Unmarshal(msg, trade)
 }
 
-// ToJSON returns json string of the Trade
+// ToJSON 返回成交记录的 JSON 字符串
 func (trade *Trade) ToJSON() []byte {
 	str, _ := json.Marshal(trade)
 	return str
 }
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (trade *Trade) MarshalJSON() ([]byte, error) {
+	obj := tradeJSON{
+		Seq:          trade.Seq,
+		ID:           trade.ID,
+		Time:         trade.Time,
+		MakerOrderID: trade.MakerOrderID,
+		TakerOrderID: trade.TakerOrderID,
+		BuyOrderID:   trade.BuyOrderID,
+		SellOrderID:  trade.SellOrderID,
+		Side:         trade.Side,
+		Amount:       trade.Amount.String(),
+		Price:        trade.Price.String(),
+		Match:        trade.Match,
+	}
+	if trade.Fees != nil {
+		obj.MakerFee = (&util.StandardBigDecimal{Val: trade.Fees.MakerFee}).String()
+		obj.MakerFeeCurrency = trade.Fees.MakerCurrency
+		obj.TakerFee = (&util.StandardBigDecimal{Val: trade.Fees.TakerFee}).String()
+		obj.TakerFeeCurrency = trade.Fees.TakerCurrency
+	}
+	return json.Marshal(&obj)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (trade *Trade) UnmarshalJSON(data []byte) error {
+	var obj tradeJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	amount, err := util.NewDecimalFromString(obj.Amount)
+	if err != nil {
+		return errors.New("invalid trade amount")
+	}
+	price, err := util.NewDecimalFromString(obj.Price)
+	if err != nil {
+		return errors.New("invalid trade price")
+	}
+	var fees *TradeFees
+	if obj.MakerFee != "" || obj.TakerFee != "" {
+		makerFee, err := util.NewDecimalFromString(obj.MakerFee)
+		if err != nil {
+			return errors.New("invalid trade maker fee")
+		}
+		takerFee, err := util.NewDecimalFromString(obj.TakerFee)
+		if err != nil {
+			return errors.New("invalid trade taker fee")
+		}
+		fees = &TradeFees{
+			MakerFee:      makerFee.Val,
+			MakerCurrency: obj.MakerFeeCurrency,
+			TakerFee:      takerFee.Val,
+			TakerCurrency: obj.TakerFeeCurrency,
+		}
+	}
+
+	*trade = Trade{
+		Seq:          obj.Seq,
+		ID:           obj.ID,
+		Time:         obj.Time,
+		MakerOrderID: obj.MakerOrderID,
+		TakerOrderID: obj.TakerOrderID,
+		BuyOrderID:   obj.BuyOrderID,
+		SellOrderID:  obj.SellOrderID,
+		Side:         obj.Side,
+		Amount:       amount,
+		Price:        price,
+		Match:        obj.Match,
+		Fees:         fees,
+	}
+	return nil
+}
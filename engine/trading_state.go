@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/goovo/matching-engine/util"
+)
+
+// TradingState 订单簿交易状态
+type TradingState string
+
+// StateContinuous 连续撮合（默认）；StateHalted 熔断暂停，拒绝新订单但允许撤单；
+// StateAuction 集合竞价，只挂单不撮合；StateClosed 休市，拒绝新订单但允许撤单；
+// StateResolved 预测市场已结算（终态），只能通过 BinaryMarket.Resolve 进入
+const (
+	StateContinuous TradingState = "continuous"
+	StateHalted     TradingState = "halted"
+	StateAuction    TradingState = "auction"
+	StateClosed     TradingState = "closed"
+	StateResolved   TradingState = "resolved"
+)
+
+// PriceBands 价格带配置，百分比为 nil 时不启用对应价格带
+// 成交价偏离参考价超过百分比（如 5 表示 5%）时自动熔断
+type PriceBands struct {
+	// 静态价格带：相对固定参考价 Reference（如昨收价）的最大偏离百分比
+	Static    *util.StandardBigDecimal
+	Reference *util.StandardBigDecimal
+	// 动态价格带：相对最新成交价的最大偏离百分比（尚无成交时不检查）
+	Dynamic *util.StandardBigDecimal
+}
+
+// TradingState 返回订单簿当前的交易状态
+func (ob *OrderBook) TradingState() TradingState {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	return ob.state
+}
+
+// SetTradingState 切换交易状态；从其他状态恢复连续撮合时，先以最新成交价为参考价执行 Uncross，
+// 撮合竞价或熔断期间形成的交叉订单
+func (ob *OrderBook) SetTradingState(state TradingState) error {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	defer ob.releaseIDs()
+
+	switch state {
+	case StateContinuous, StateHalted, StateAuction, StateClosed:
+	default:
+		return errors.New("invalid trading state")
+	}
+	if ob.state == StateResolved {
+		return errors.New("market resolved")
+	}
+	if state == StateContinuous && ob.state != StateContinuous {
+		ob.uncross(nil)
+		return nil
+	}
+	ob.setState(state, StateChangeManual)
+	ob.triggerStops()
+	return nil
+}
+
+// SetPriceBands 设置价格带（熔断）配置
+func (ob *OrderBook) SetPriceBands(bands PriceBands) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	ob.bands = bands
+}
+
+// setState 切换交易状态并通知 StateListener（调用方持有锁）
+func (ob *OrderBook) setState(state TradingState, reason StateChangeReason) {
+	if ob.state == state {
+		return
+	}
+	ob.state = state
+	if ob.stateListener != nil {
+		ob.stateListener.OnTradingStateChanged(state, reason)
+	}
+	ob.emitState(state, reason)
+}
+
+// rejectNotTrading 熔断、休市或已结算时拒绝新订单，返回 true 表示已拒绝
+func (ob *OrderBook) rejectNotTrading(order *Order) bool {
+	switch ob.state {
+	case StateHalted:
+		ob.rejectOrder(order, RejectHalted)
+		return true
+	case StateClosed:
+		ob.rejectOrder(order, RejectClosed)
+		return true
+	case StateResolved:
+		ob.rejectOrder(order, RejectResolved)
+		return true
+	}
+	return false
+}
+
+// breachesBand 判断以 price 成交是否超出价格带；超出时自动熔断并返回 true
+func (ob *OrderBook) breachesBand(price *util.StandardBigDecimal) bool {
+	breached := ob.outsideBands(price.Val)
+	if breached {
+		ob.setState(StateHalted, StateChangeVolatility)
+	}
+	return breached
+}
+
+// outsideBands 判断以 price 成交是否超出静态或动态价格带（不熔断）
+func (ob *OrderBook) outsideBands(price int64) bool {
+	bands := ob.bands
+	return bands.Static != nil && bands.Reference != nil && outsideBand(price, bands.Reference.Val, bands.Static) ||
+		bands.Dynamic != nil && ob.lastPrice > 0 && outsideBand(price, ob.lastPrice, bands.Dynamic)
+}
+
+// outsideBand 判断 price 相对 reference 的偏离是否超过 percent%
+func outsideBand(price, reference int64, percent *util.StandardBigDecimal) bool {
+	diff := price - reference
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > util.MulDivFloor(reference, percent.Val, 100*util.SCALE)
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"testing"
+)
+
+type stateListener struct {
+	rejectListener
+	States []TradingState
+	Reason StateChangeReason
+}
+
+func (l *stateListener) OnTradingStateChanged(state TradingState, reason StateChangeReason) {
+	l.States = append(l.States, state)
+	l.Reason = reason
+}
+
+func TestHaltRejectsOrders(t *testing.T) {
+	listener := &stateListener{}
+	ob := NewOrderBook(listener)
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+
+	if err := ob.SetTradingState(StateHalted); err != nil {
+		t.Fatal(err)
+	}
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.ProcessMarket(*NewOrder("m1", Buy, DecimalBig("1.0"), DecimalBig("1.0")))
+	ob.Process(newStop("st", Buy, "1.0", "110.0", "105.0"))
+	if listener.Rejected["b1"] != RejectHalted || listener.Rejected["m1"] != RejectHalted || listener.Rejected["st"] != RejectHalted {
+		t.Fatalf("orders should be rejected while halted (have: %v)", listener.Rejected)
+	}
+	if len(listener.Trades) != 0 {
+		t.Fatal("halted book should not match")
+	}
+	if ob.CancelOrder("s1") == nil {
+		t.Fatal("cancels should be allowed while halted")
+	}
+
+	ob.SetTradingState(StateClosed)
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("100.0")))
+	if listener.Rejected["b2"] != RejectClosed {
+		t.Fatal("orders should be rejected while closed")
+	}
+	if err := ob.SetTradingState("paused"); err == nil {
+		t.Fatal("unknown state should fail")
+	}
+	if ob.TradingState() != StateClosed || len(listener.States) != 2 {
+		t.Fatalf("unexpected state transitions (have: %v)", listener.States)
+	}
+}
+
+func TestDynamicBandHalts(t *testing.T) {
+	listener := &stateListener{}
+	ob := NewOrderBook(listener)
+	ob.SetPriceBands(PriceBands{Dynamic: DecimalBig("5.0")})
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("103.0")))
+	ob.Process(*NewOrder("s3", Sell, DecimalBig("1.0"), DecimalBig("110.0")))
+
+	// 首笔成交无最新成交价，不检查动态价格带；110 相对最新成交价 103 偏离超过 5%
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("3.0"), DecimalBig("110.0")))
+	if len(listener.Trades) != 2 {
+		t.Fatalf("sweep should stop before breaching band (have: %+v)", listener.Trades)
+	}
+	if ob.TradingState() != StateHalted || listener.Reason != StateChangeVolatility {
+		t.Fatal("band breach should trigger volatility halt")
+	}
+	if rest := ob.Arena.Get(indexOf(ob, "b1")); rest.Amount.Cmp(DecimalBig("1.0")) != 0 {
+		t.Fatal("taker remainder should rest")
+	}
+
+	// 恢复连续撮合时撮合交叉订单
+	if err := ob.SetTradingState(StateContinuous); err != nil {
+		t.Fatal(err)
+	}
+	if ob.TradingState() != StateContinuous || len(listener.Trades) != 3 || listener.Trades[2].Price != DecimalBig("110.0").Val {
+		t.Fatalf("resume should uncross the halted book (have: %+v)", listener.Trades)
+	}
+}
+
+func TestStaticBandMarketOrder(t *testing.T) {
+	listener := &cancelReasonListener{}
+	ob := NewOrderBook(listener)
+	ob.SetPriceBands(PriceBands{Static: DecimalBig("10.0"), Reference: DecimalBig("100.0")})
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("95.0")))
+	ob.Process(*NewOrder("b2", Buy, DecimalBig("1.0"), DecimalBig("89.0")))
+
+	ob.ProcessMarket(*NewOrder("m", Sell, DecimalBig("2.0"), DecimalBig("1.0")))
+	if len(listener.Trades) != 1 || listener.Reasons["m"] != CancelVolatilityHalt {
+		t.Fatalf("market order should stop at band and cancel remainder (have: %+v, %v)", listener.Trades, listener.Reasons)
+	}
+	if ob.TradingState() != StateHalted {
+		t.Fatal("band breach should halt the book")
+	}
+}
+
+func TestAuctionStateViaSetTradingState(t *testing.T) {
+	listener := &MockListener{}
+	ob := NewOrderBook(listener)
+	ob.SetTradingState(StateAuction)
+	ob.Process(*NewOrder("b1", Buy, DecimalBig("1.0"), DecimalBig("101.0")))
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	if !ob.InAuction() || len(listener.Trades) != 0 {
+		t.Fatal("auction state should accumulate orders")
+	}
+	ob.SetTradingState(StateContinuous)
+	if len(listener.Trades) != 1 || ob.InAuction() {
+		t.Fatal("leaving auction should uncross")
+	}
+}
+
+func TestFOKStopsAtPriceBand(t *testing.T) {
+	listener := &cancelReasonListener{}
+	ob := NewOrderBook(listener)
+	ob.SetPriceBands(PriceBands{Static: DecimalBig("5.0"), Reference: DecimalBig("100.0")})
+	ob.Process(*NewOrder("s1", Sell, DecimalBig("1.0"), DecimalBig("100.0")))
+	ob.Process(*NewOrder("s2", Sell, DecimalBig("1.0"), DecimalBig("110.0")))
+
+	fok := *NewOrder("fok", Buy, DecimalBig("2.0"), DecimalBig("110.0"))
+	fok.TimeInForce = FOK
+	ob.Process(fok)
+	if len(listener.Trades) != 0 || listener.Reasons["fok"] != CancelFillOrKill {
+		t.Fatalf("FOK should be killed before any fill (have: %+v, %v)", listener.Trades, listener.Reasons)
+	}
+	if ob.TradingState() != StateContinuous {
+		t.Fatal("killed FOK should not halt the book")
+	}
+}
@@ -47,15 +47,112 @@ func (Side) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_770b178c3aab763f, []int{0}
 }
 
+type TimeInForce int32
+
+const (
+	TimeInForce_GTC TimeInForce = 0
+	TimeInForce_IOC TimeInForce = 1
+	TimeInForce_FOK TimeInForce = 2
+)
+
+var TimeInForce_name = map[int32]string{
+	0: "GTC",
+	1: "IOC",
+	2: "FOK",
+}
+
+var TimeInForce_value = map[string]int32{
+	"GTC": 0,
+	"IOC": 1,
+	"FOK": 2,
+}
+
+func (x TimeInForce) String() string {
+	return proto.EnumName(TimeInForce_name, int32(x))
+}
+
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{1}
+}
+
+type PostOnly int32
+
+const (
+	PostOnly_none    PostOnly = 0
+	PostOnly_reject  PostOnly = 1
+	PostOnly_reprice PostOnly = 2
+)
+
+var PostOnly_name = map[int32]string{
+	0: "none",
+	1: "reject",
+	2: "reprice",
+}
+
+var PostOnly_value = map[string]int32{
+	"none":    0,
+	"reject":  1,
+	"reprice": 2,
+}
+
+func (x PostOnly) String() string {
+	return proto.EnumName(PostOnly_name, int32(x))
+}
+
+func (PostOnly) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{2}
+}
+
+type STP int32
+
+const (
+	STP_cancel_newest        STP = 0
+	STP_cancel_oldest        STP = 1
+	STP_cancel_both          STP = 2
+	STP_decrement_and_cancel STP = 3
+)
+
+var STP_name = map[int32]string{
+	0: "cancel_newest",
+	1: "cancel_oldest",
+	2: "cancel_both",
+	3: "decrement_and_cancel",
+}
+
+var STP_value = map[string]int32{
+	"cancel_newest":        0,
+	"cancel_oldest":        1,
+	"cancel_both":          2,
+	"decrement_and_cancel": 3,
+}
+
+func (x STP) String() string {
+	return proto.EnumName(STP_name, int32(x))
+}
+
+func (STP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{3}
+}
+
 type Order struct {
-	Type                 Side     `protobuf:"varint,1,opt,name=Type,json=type,proto3,enum=Side" json:"Type,omitempty"`
-	ID                   string   `protobuf:"bytes,2,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
-	Amount               string   `protobuf:"bytes,3,opt,name=Amount,json=amount,proto3" json:"Amount,omitempty"`
-	Price                string   `protobuf:"bytes,4,opt,name=Price,json=price,proto3" json:"Price,omitempty"`
-	Pair                 string   `protobuf:"bytes,5,opt,name=Pair,json=pair,proto3" json:"Pair,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Type                 Side        `protobuf:"varint,1,opt,name=Type,json=type,proto3,enum=Side" json:"Type,omitempty"`
+	ID                   string      `protobuf:"bytes,2,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	Amount               string      `protobuf:"bytes,3,opt,name=Amount,json=amount,proto3" json:"Amount,omitempty"`
+	Price                string      `protobuf:"bytes,4,opt,name=Price,json=price,proto3" json:"Price,omitempty"`
+	Pair                 string      `protobuf:"bytes,5,opt,name=Pair,json=pair,proto3" json:"Pair,omitempty"`
+	TimeInForce          TimeInForce `protobuf:"varint,6,opt,name=TimeInForce,json=time_in_force,proto3,enum=TimeInForce" json:"TimeInForce,omitempty"`
+	PostOnly             PostOnly    `protobuf:"varint,7,opt,name=PostOnly,json=post_only,proto3,enum=PostOnly" json:"PostOnly,omitempty"`
+	DisplayAmount        string      `protobuf:"bytes,8,opt,name=DisplayAmount,json=display_amount,proto3" json:"DisplayAmount,omitempty"`
+	StopPrice            string      `protobuf:"bytes,9,opt,name=StopPrice,json=stop_price,proto3" json:"StopPrice,omitempty"`
+	Account              string      `protobuf:"bytes,10,opt,name=Account,json=account,proto3" json:"Account,omitempty"`
+	STP                  STP         `protobuf:"varint,11,opt,name=STP,json=stp,proto3,enum=STP" json:"STP,omitempty"`
+	Funds                string      `protobuf:"bytes,12,opt,name=Funds,json=funds,proto3" json:"Funds,omitempty"`
+	ProtectionPrice      string      `protobuf:"bytes,13,opt,name=ProtectionPrice,json=protection_price,proto3" json:"ProtectionPrice,omitempty"`
+	MaxSlippage          string      `protobuf:"bytes,14,opt,name=MaxSlippage,json=max_slippage,proto3" json:"MaxSlippage,omitempty"`
+	Market               bool        `protobuf:"varint,15,opt,name=Market,json=market,proto3" json:"Market,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *Order) Reset()         { *m = Order{} }
@@ -118,6 +215,139 @@ func (m *Order) GetPair() string {
 	return ""
 }
 
+func (m *Order) GetTimeInForce() TimeInForce {
+	if m != nil {
+		return m.TimeInForce
+	}
+	return TimeInForce_GTC
+}
+
+func (m *Order) GetPostOnly() PostOnly {
+	if m != nil {
+		return m.PostOnly
+	}
+	return PostOnly_none
+}
+
+func (m *Order) GetDisplayAmount() string {
+	if m != nil {
+		return m.DisplayAmount
+	}
+	return ""
+}
+
+func (m *Order) GetStopPrice() string {
+	if m != nil {
+		return m.StopPrice
+	}
+	return ""
+}
+
+func (m *Order) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *Order) GetSTP() STP {
+	if m != nil {
+		return m.STP
+	}
+	return STP_cancel_newest
+}
+
+func (m *Order) GetFunds() string {
+	if m != nil {
+		return m.Funds
+	}
+	return ""
+}
+
+func (m *Order) GetProtectionPrice() string {
+	if m != nil {
+		return m.ProtectionPrice
+	}
+	return ""
+}
+
+func (m *Order) GetMaxSlippage() string {
+	if m != nil {
+		return m.MaxSlippage
+	}
+	return ""
+}
+
+func (m *Order) GetMarket() bool {
+	if m != nil {
+		return m.Market
+	}
+	return false
+}
+
+type OrderGroup struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	Pair                 string   `protobuf:"bytes,2,opt,name=Pair,json=pair,proto3" json:"Pair,omitempty"`
+	Entry                *Order   `protobuf:"bytes,3,opt,name=Entry,json=entry,proto3" json:"Entry,omitempty"`
+	Legs                 []*Order `protobuf:"bytes,4,rep,name=Legs,json=legs,proto3" json:"Legs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderGroup) Reset()         { *m = OrderGroup{} }
+func (m *OrderGroup) String() string { return proto.CompactTextString(m) }
+func (*OrderGroup) ProtoMessage()    {}
+func (*OrderGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{1}
+}
+
+func (m *OrderGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderGroup.Unmarshal(m, b)
+}
+func (m *OrderGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderGroup.Marshal(b, m, deterministic)
+}
+func (m *OrderGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderGroup.Merge(m, src)
+}
+func (m *OrderGroup) XXX_Size() int {
+	return xxx_messageInfo_OrderGroup.Size(m)
+}
+func (m *OrderGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderGroup proto.InternalMessageInfo
+
+func (m *OrderGroup) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *OrderGroup) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *OrderGroup) GetEntry() *Order {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *OrderGroup) GetLegs() []*Order {
+	if m != nil {
+		return m.Legs
+	}
+	return nil
+}
+
 type OutputOrders struct {
 	OrdersProcessed      string   `protobuf:"bytes,1,opt,name=OrdersProcessed,json=orders_processed,proto3" json:"OrdersProcessed,omitempty"`
 	PartialOrder         string   `protobuf:"bytes,2,opt,name=PartialOrder,json=partial_order,proto3" json:"PartialOrder,omitempty"`
@@ -130,7 +360,7 @@ func (m *OutputOrders) Reset()         { *m = OutputOrders{} }
 func (m *OutputOrders) String() string { return proto.CompactTextString(m) }
 func (*OutputOrders) ProtoMessage()    {}
 func (*OutputOrders) Descriptor() ([]byte, []int) {
-	return fileDescriptor_770b178c3aab763f, []int{1}
+	return fileDescriptor_770b178c3aab763f, []int{2}
 }
 
 func (m *OutputOrders) XXX_Unmarshal(b []byte) error {
@@ -177,7 +407,7 @@ func (m *BookInput) Reset()         { *m = BookInput{} }
 func (m *BookInput) String() string { return proto.CompactTextString(m) }
 func (*BookInput) ProtoMessage()    {}
 func (*BookInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_770b178c3aab763f, []int{2}
+	return fileDescriptor_770b178c3aab763f, []int{3}
 }
 
 func (m *BookInput) XXX_Unmarshal(b []byte) error {
@@ -223,7 +453,7 @@ func (m *BookArray) Reset()         { *m = BookArray{} }
 func (m *BookArray) String() string { return proto.CompactTextString(m) }
 func (*BookArray) ProtoMessage()    {}
 func (*BookArray) Descriptor() ([]byte, []int) {
-	return fileDescriptor_770b178c3aab763f, []int{3}
+	return fileDescriptor_770b178c3aab763f, []int{4}
 }
 
 func (m *BookArray) XXX_Unmarshal(b []byte) error {
@@ -254,6 +484,7 @@ func (m *BookArray) GetPriceAmount() []string {
 type BookOutput struct {
 	Buys                 []*BookArray `protobuf:"bytes,1,rep,name=Buys,json=buys,proto3" json:"Buys,omitempty"`
 	Sells                []*BookArray `protobuf:"bytes,2,rep,name=Sells,json=sells,proto3" json:"Sells,omitempty"`
+	State                string       `protobuf:"bytes,3,opt,name=State,json=state,proto3" json:"State,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -263,7 +494,7 @@ func (m *BookOutput) Reset()         { *m = BookOutput{} }
 func (m *BookOutput) String() string { return proto.CompactTextString(m) }
 func (*BookOutput) ProtoMessage()    {}
 func (*BookOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_770b178c3aab763f, []int{4}
+	return fileDescriptor_770b178c3aab763f, []int{5}
 }
 
 func (m *BookOutput) XXX_Unmarshal(b []byte) error {
@@ -298,162 +529,780 @@ func (m *BookOutput) GetSells() []*BookArray {
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("Side", Side_name, Side_value)
-	proto.RegisterType((*Order)(nil), "Order")
-	proto.RegisterType((*OutputOrders)(nil), "OutputOrders")
-	proto.RegisterType((*BookInput)(nil), "BookInput")
-	proto.RegisterType((*BookArray)(nil), "BookArray")
-	proto.RegisterType((*BookOutput)(nil), "BookOutput")
+func (m *BookOutput) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
 }
 
-func init() {
-	proto.RegisterFile("engine.proto", fileDescriptor_770b178c3aab763f)
+type MassCancelInput struct {
+	Pair                 string   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Account              string   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Side                 string   `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"`
+	Price                string   `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-var fileDescriptor_770b178c3aab763f = []byte{
-	// 384 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x4d, 0x6f, 0xd4, 0x30,
-	0x10, 0x25, 0x89, 0x93, 0x92, 0xd9, 0x6c, 0x59, 0x8d, 0x10, 0x72, 0x7b, 0x40, 0x21, 0x20, 0xb4,
-	0x70, 0xc8, 0x61, 0x11, 0x3f, 0xa0, 0xe5, 0x43, 0xea, 0x01, 0x1a, 0xa5, 0x9c, 0x59, 0x79, 0x13,
-	0x0b, 0xac, 0x66, 0x13, 0xcb, 0x71, 0x0e, 0xf9, 0x1b, 0xfc, 0x62, 0xe4, 0x71, 0x58, 0x84, 0x50,
-	0x4f, 0xf1, 0x3c, 0xbf, 0x99, 0x79, 0xef, 0xc5, 0x90, 0xc9, 0xfe, 0x87, 0xea, 0x65, 0xa9, 0xcd,
-	0x60, 0x87, 0xc2, 0x42, 0x7c, 0x6b, 0x5a, 0x69, 0xf0, 0x02, 0xd8, 0xb7, 0x59, 0x4b, 0x1e, 0xe4,
-	0xc1, 0xf6, 0x7c, 0x17, 0x97, 0x77, 0xaa, 0x95, 0x35, 0xb3, 0xb3, 0x96, 0x78, 0x0e, 0xe1, 0xcd,
-	0x47, 0x1e, 0xe6, 0xc1, 0x36, 0xad, 0x43, 0xd5, 0xe2, 0x33, 0x48, 0xae, 0x8e, 0xc3, 0xd4, 0x5b,
-	0x1e, 0x11, 0x96, 0x08, 0xaa, 0xf0, 0x29, 0xc4, 0x95, 0x51, 0x8d, 0xe4, 0x8c, 0xe0, 0x58, 0xbb,
-	0x02, 0x11, 0x58, 0x25, 0x94, 0xe1, 0x31, 0x81, 0x4c, 0x0b, 0x65, 0x8a, 0xef, 0x90, 0xdd, 0x4e,
-	0x56, 0x4f, 0x96, 0x76, 0x8f, 0xf8, 0x06, 0x9e, 0xf8, 0x53, 0x65, 0x86, 0x46, 0x8e, 0xa3, 0x6c,
-	0x49, 0x47, 0x5a, 0x6f, 0x06, 0x82, 0xf7, 0xfa, 0x0f, 0x8e, 0x2f, 0x21, 0xab, 0x84, 0xb1, 0x4a,
-	0x74, 0xd4, 0xb1, 0xc8, 0x5a, 0x6b, 0x8f, 0xed, 0x89, 0x5f, 0xbc, 0x87, 0xf4, 0x7a, 0x18, 0xee,
-	0x6f, 0x7a, 0x3d, 0x59, 0x27, 0xc0, 0x2d, 0x5d, 0x26, 0xd2, 0xd9, 0x49, 0xed, 0xd4, 0x51, 0x59,
-	0x6a, 0x8f, 0x6a, 0x5f, 0x14, 0xa5, 0x6f, 0xbb, 0x32, 0x46, 0xcc, 0xf8, 0x02, 0x32, 0x32, 0xb0,
-	0xf7, 0xee, 0x78, 0x90, 0x47, 0xdb, 0xb4, 0x5e, 0x11, 0xe6, 0xed, 0x17, 0x5f, 0x01, 0x1c, 0xdf,
-	0x5b, 0xc1, 0xe7, 0xc0, 0xae, 0xa7, 0x79, 0x24, 0xe2, 0x6a, 0x07, 0xe5, 0x69, 0x54, 0xcd, 0x0e,
-	0xd3, 0x3c, 0x62, 0x0e, 0xf1, 0x9d, 0xec, 0xba, 0x91, 0x87, 0xff, 0x11, 0xe2, 0xd1, 0x5d, 0xbc,
-	0xbd, 0x00, 0xe6, 0x62, 0xc7, 0x33, 0x88, 0x0e, 0xd3, 0xbc, 0x79, 0x84, 0x8f, 0x81, 0xb9, 0x9b,
-	0x4d, 0xb0, 0xfb, 0x15, 0x40, 0xf2, 0x89, 0x7e, 0x1c, 0xe6, 0x70, 0xb6, 0xc4, 0x84, 0x49, 0x49,
-	0x21, 0x5c, 0xae, 0xcb, 0x7f, 0xe2, 0x7c, 0x0d, 0xeb, 0x85, 0xf1, 0x45, 0x98, 0x7b, 0x69, 0x1f,
-	0xe2, 0x71, 0x48, 0x3e, 0x88, 0xbe, 0x91, 0xdd, 0x89, 0xb0, 0x7c, 0xf1, 0x15, 0xa4, 0x9f, 0xa5,
-	0x6d, 0x7e, 0x3a, 0x89, 0xe8, 0x95, 0x52, 0x98, 0x97, 0xab, 0xf2, 0xaf, 0xe3, 0x43, 0x42, 0x6f,
-	0xe8, 0xdd, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8d, 0x39, 0x65, 0xfd, 0x53, 0x02, 0x00, 0x00,
+func (m *MassCancelInput) Reset()         { *m = MassCancelInput{} }
+func (m *MassCancelInput) String() string { return proto.CompactTextString(m) }
+func (*MassCancelInput) ProtoMessage()    {}
+func (*MassCancelInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{6}
 }
 
-// 下面的空引用用于防止未使用的导入导致编译错误
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// 编译期断言：确保该生成文件与当前使用的 grpc 包版本兼容
-const _ = grpc.SupportPackageIsVersion6
-
-// EngineClient 定义了 Engine 服务的客户端接口
-//
-// 关于 ctx 的使用、以及关闭/结束流式 RPC 的语义，请参考：
-// https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
-type EngineClient interface {
-	Process(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error)
-	ProcessMarket(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error)
-	Cancel(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error)
-	FetchBook(ctx context.Context, in *BookInput, opts ...grpc.CallOption) (*BookOutput, error)
+func (m *MassCancelInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MassCancelInput.Unmarshal(m, b)
 }
-
-type engineClient struct {
-	cc grpc.ClientConnInterface
+func (m *MassCancelInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MassCancelInput.Marshal(b, m, deterministic)
 }
-
-func NewEngineClient(cc grpc.ClientConnInterface) EngineClient {
-	return &engineClient{cc}
+func (m *MassCancelInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MassCancelInput.Merge(m, src)
+}
+func (m *MassCancelInput) XXX_Size() int {
+	return xxx_messageInfo_MassCancelInput.Size(m)
+}
+func (m *MassCancelInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_MassCancelInput.DiscardUnknown(m)
 }
 
-func (c *engineClient) Process(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error) {
-	out := new(OutputOrders)
-	err := c.cc.Invoke(ctx, "/Engine/Process", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_MassCancelInput proto.InternalMessageInfo
+
+func (m *MassCancelInput) GetPair() string {
+	if m != nil {
+		return m.Pair
 	}
-	return out, nil
+	return ""
 }
 
-func (c *engineClient) ProcessMarket(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error) {
-	out := new(OutputOrders)
-	err := c.cc.Invoke(ctx, "/Engine/ProcessMarket", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MassCancelInput) GetAccount() string {
+	if m != nil {
+		return m.Account
 	}
-	return out, nil
+	return ""
 }
 
-func (c *engineClient) Cancel(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error) {
-	out := new(Order)
-	err := c.cc.Invoke(ctx, "/Engine/Cancel", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MassCancelInput) GetSide() string {
+	if m != nil {
+		return m.Side
 	}
-	return out, nil
+	return ""
 }
 
-func (c *engineClient) FetchBook(ctx context.Context, in *BookInput, opts ...grpc.CallOption) (*BookOutput, error) {
-	out := new(BookOutput)
-	err := c.cc.Invoke(ctx, "/Engine/FetchBook", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MassCancelInput) GetPrice() string {
+	if m != nil {
+		return m.Price
 	}
-	return out, nil
+	return ""
 }
 
-// EngineServer 定义了 Engine 服务的服务端接口
-type EngineServer interface {
-	Process(context.Context, *Order) (*OutputOrders, error)
-	ProcessMarket(context.Context, *Order) (*OutputOrders, error)
-	Cancel(context.Context, *Order) (*Order, error)
-	FetchBook(context.Context, *BookInput) (*BookOutput, error)
+type MassCancelOutput struct {
+	Buys                 int64    `protobuf:"varint,1,opt,name=buys,proto3" json:"buys,omitempty"`
+	Sells                int64    `protobuf:"varint,2,opt,name=sells,proto3" json:"sells,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// UnimplementedEngineServer 可嵌入以提供向前兼容的默认实现
-type UnimplementedEngineServer struct {
+func (m *MassCancelOutput) Reset()         { *m = MassCancelOutput{} }
+func (m *MassCancelOutput) String() string { return proto.CompactTextString(m) }
+func (*MassCancelOutput) ProtoMessage()    {}
+func (*MassCancelOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{7}
 }
 
-func (*UnimplementedEngineServer) Process(ctx context.Context, req *Order) (*OutputOrders, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
+func (m *MassCancelOutput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MassCancelOutput.Unmarshal(m, b)
 }
-func (*UnimplementedEngineServer) ProcessMarket(ctx context.Context, req *Order) (*OutputOrders, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ProcessMarket not implemented")
+func (m *MassCancelOutput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MassCancelOutput.Marshal(b, m, deterministic)
 }
-func (*UnimplementedEngineServer) Cancel(ctx context.Context, req *Order) (*Order, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+func (m *MassCancelOutput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MassCancelOutput.Merge(m, src)
 }
-func (*UnimplementedEngineServer) FetchBook(ctx context.Context, req *BookInput) (*BookOutput, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FetchBook not implemented")
+func (m *MassCancelOutput) XXX_Size() int {
+	return xxx_messageInfo_MassCancelOutput.Size(m)
 }
-
-func RegisterEngineServer(s *grpc.Server, srv EngineServer) {
-	s.RegisterService(&_Engine_serviceDesc, srv)
+func (m *MassCancelOutput) XXX_DiscardUnknown() {
+	xxx_messageInfo_MassCancelOutput.DiscardUnknown(m)
 }
 
-func _Engine_Process_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Order)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(EngineServer).Process(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/Engine/Process",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EngineServer).Process(ctx, req.(*Order))
+var xxx_messageInfo_MassCancelOutput proto.InternalMessageInfo
+
+func (m *MassCancelOutput) GetBuys() int64 {
+	if m != nil {
+		return m.Buys
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Engine_ProcessMarket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Order)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *MassCancelOutput) GetSells() int64 {
+	if m != nil {
+		return m.Sells
 	}
-	if interceptor == nil {
+	return 0
+}
+
+type TradingStateInput struct {
+	Pair                 string   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TradingStateInput) Reset()         { *m = TradingStateInput{} }
+func (m *TradingStateInput) String() string { return proto.CompactTextString(m) }
+func (*TradingStateInput) ProtoMessage()    {}
+func (*TradingStateInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{8}
+}
+
+func (m *TradingStateInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradingStateInput.Unmarshal(m, b)
+}
+func (m *TradingStateInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradingStateInput.Marshal(b, m, deterministic)
+}
+func (m *TradingStateInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradingStateInput.Merge(m, src)
+}
+func (m *TradingStateInput) XXX_Size() int {
+	return xxx_messageInfo_TradingStateInput.Size(m)
+}
+func (m *TradingStateInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradingStateInput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradingStateInput proto.InternalMessageInfo
+
+func (m *TradingStateInput) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *TradingStateInput) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type TradingStateOutput struct {
+	Pair                 string   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TradingStateOutput) Reset()         { *m = TradingStateOutput{} }
+func (m *TradingStateOutput) String() string { return proto.CompactTextString(m) }
+func (*TradingStateOutput) ProtoMessage()    {}
+func (*TradingStateOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{9}
+}
+
+func (m *TradingStateOutput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradingStateOutput.Unmarshal(m, b)
+}
+func (m *TradingStateOutput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradingStateOutput.Marshal(b, m, deterministic)
+}
+func (m *TradingStateOutput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradingStateOutput.Merge(m, src)
+}
+func (m *TradingStateOutput) XXX_Size() int {
+	return xxx_messageInfo_TradingStateOutput.Size(m)
+}
+func (m *TradingStateOutput) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradingStateOutput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradingStateOutput proto.InternalMessageInfo
+
+func (m *TradingStateOutput) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *TradingStateOutput) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type GetOrderInput struct {
+	Pair                 string   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOrderInput) Reset()         { *m = GetOrderInput{} }
+func (m *GetOrderInput) String() string { return proto.CompactTextString(m) }
+func (*GetOrderInput) ProtoMessage()    {}
+func (*GetOrderInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{10}
+}
+
+func (m *GetOrderInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOrderInput.Unmarshal(m, b)
+}
+func (m *GetOrderInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOrderInput.Marshal(b, m, deterministic)
+}
+func (m *GetOrderInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOrderInput.Merge(m, src)
+}
+func (m *GetOrderInput) XXX_Size() int {
+	return xxx_messageInfo_GetOrderInput.Size(m)
+}
+func (m *GetOrderInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOrderInput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOrderInput proto.InternalMessageInfo
+
+func (m *GetOrderInput) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *GetOrderInput) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type OrderState struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	Pair                 string   `protobuf:"bytes,2,opt,name=Pair,json=pair,proto3" json:"Pair,omitempty"`
+	Type                 Side     `protobuf:"varint,3,opt,name=Type,json=type,proto3,enum=Side" json:"Type,omitempty"`
+	Account              string   `protobuf:"bytes,4,opt,name=Account,json=account,proto3" json:"Account,omitempty"`
+	Price                string   `protobuf:"bytes,5,opt,name=Price,json=price,proto3" json:"Price,omitempty"`
+	StopPrice            string   `protobuf:"bytes,6,opt,name=StopPrice,json=stop_price,proto3" json:"StopPrice,omitempty"`
+	Amount               string   `protobuf:"bytes,7,opt,name=Amount,json=amount,proto3" json:"Amount,omitempty"`
+	Remaining            string   `protobuf:"bytes,8,opt,name=Remaining,json=remaining,proto3" json:"Remaining,omitempty"`
+	Filled               string   `protobuf:"bytes,9,opt,name=Filled,json=filled,proto3" json:"Filled,omitempty"`
+	Status               string   `protobuf:"bytes,10,opt,name=Status,json=status,proto3" json:"Status,omitempty"`
+	QueuePosition        int64    `protobuf:"varint,11,opt,name=QueuePosition,json=queue_position,proto3" json:"QueuePosition,omitempty"`
+	VolumeAhead          string   `protobuf:"bytes,12,opt,name=VolumeAhead,json=volume_ahead,proto3" json:"VolumeAhead,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderState) Reset()         { *m = OrderState{} }
+func (m *OrderState) String() string { return proto.CompactTextString(m) }
+func (*OrderState) ProtoMessage()    {}
+func (*OrderState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{11}
+}
+
+func (m *OrderState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderState.Unmarshal(m, b)
+}
+func (m *OrderState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderState.Marshal(b, m, deterministic)
+}
+func (m *OrderState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderState.Merge(m, src)
+}
+func (m *OrderState) XXX_Size() int {
+	return xxx_messageInfo_OrderState.Size(m)
+}
+func (m *OrderState) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderState proto.InternalMessageInfo
+
+func (m *OrderState) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *OrderState) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *OrderState) GetType() Side {
+	if m != nil {
+		return m.Type
+	}
+	return Side_buy
+}
+
+func (m *OrderState) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *OrderState) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *OrderState) GetStopPrice() string {
+	if m != nil {
+		return m.StopPrice
+	}
+	return ""
+}
+
+func (m *OrderState) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *OrderState) GetRemaining() string {
+	if m != nil {
+		return m.Remaining
+	}
+	return ""
+}
+
+func (m *OrderState) GetFilled() string {
+	if m != nil {
+		return m.Filled
+	}
+	return ""
+}
+
+func (m *OrderState) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *OrderState) GetQueuePosition() int64 {
+	if m != nil {
+		return m.QueuePosition
+	}
+	return 0
+}
+
+func (m *OrderState) GetVolumeAhead() string {
+	if m != nil {
+		return m.VolumeAhead
+	}
+	return ""
+}
+
+type ListOpenOrdersInput struct {
+	Pair                 string   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Account              string   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Side                 string   `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOpenOrdersInput) Reset()         { *m = ListOpenOrdersInput{} }
+func (m *ListOpenOrdersInput) String() string { return proto.CompactTextString(m) }
+func (*ListOpenOrdersInput) ProtoMessage()    {}
+func (*ListOpenOrdersInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{12}
+}
+
+func (m *ListOpenOrdersInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOpenOrdersInput.Unmarshal(m, b)
+}
+func (m *ListOpenOrdersInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOpenOrdersInput.Marshal(b, m, deterministic)
+}
+func (m *ListOpenOrdersInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOpenOrdersInput.Merge(m, src)
+}
+func (m *ListOpenOrdersInput) XXX_Size() int {
+	return xxx_messageInfo_ListOpenOrdersInput.Size(m)
+}
+func (m *ListOpenOrdersInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOpenOrdersInput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOpenOrdersInput proto.InternalMessageInfo
+
+func (m *ListOpenOrdersInput) GetPair() string {
+	if m != nil {
+		return m.Pair
+	}
+	return ""
+}
+
+func (m *ListOpenOrdersInput) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *ListOpenOrdersInput) GetSide() string {
+	if m != nil {
+		return m.Side
+	}
+	return ""
+}
+
+type ListOpenOrdersOutput struct {
+	Orders               []*OrderState `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListOpenOrdersOutput) Reset()         { *m = ListOpenOrdersOutput{} }
+func (m *ListOpenOrdersOutput) String() string { return proto.CompactTextString(m) }
+func (*ListOpenOrdersOutput) ProtoMessage()    {}
+func (*ListOpenOrdersOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_770b178c3aab763f, []int{13}
+}
+
+func (m *ListOpenOrdersOutput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOpenOrdersOutput.Unmarshal(m, b)
+}
+func (m *ListOpenOrdersOutput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOpenOrdersOutput.Marshal(b, m, deterministic)
+}
+func (m *ListOpenOrdersOutput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOpenOrdersOutput.Merge(m, src)
+}
+func (m *ListOpenOrdersOutput) XXX_Size() int {
+	return xxx_messageInfo_ListOpenOrdersOutput.Size(m)
+}
+func (m *ListOpenOrdersOutput) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOpenOrdersOutput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOpenOrdersOutput proto.InternalMessageInfo
+
+func (m *ListOpenOrdersOutput) GetOrders() []*OrderState {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("Side", Side_name, Side_value)
+	proto.RegisterEnum("TimeInForce", TimeInForce_name, TimeInForce_value)
+	proto.RegisterEnum("PostOnly", PostOnly_name, PostOnly_value)
+	proto.RegisterEnum("STP", STP_name, STP_value)
+	proto.RegisterType((*Order)(nil), "Order")
+	proto.RegisterType((*OrderGroup)(nil), "OrderGroup")
+	proto.RegisterType((*OutputOrders)(nil), "OutputOrders")
+	proto.RegisterType((*BookInput)(nil), "BookInput")
+	proto.RegisterType((*BookArray)(nil), "BookArray")
+	proto.RegisterType((*BookOutput)(nil), "BookOutput")
+	proto.RegisterType((*MassCancelInput)(nil), "MassCancelInput")
+	proto.RegisterType((*MassCancelOutput)(nil), "MassCancelOutput")
+	proto.RegisterType((*TradingStateInput)(nil), "TradingStateInput")
+	proto.RegisterType((*TradingStateOutput)(nil), "TradingStateOutput")
+	proto.RegisterType((*GetOrderInput)(nil), "GetOrderInput")
+	proto.RegisterType((*OrderState)(nil), "OrderState")
+	proto.RegisterType((*ListOpenOrdersInput)(nil), "ListOpenOrdersInput")
+	proto.RegisterType((*ListOpenOrdersOutput)(nil), "ListOpenOrdersOutput")
+}
+
+func init() {
+	proto.RegisterFile("engine.proto", fileDescriptor_770b178c3aab763f)
+}
+
+var fileDescriptor_770b178c3aab763f = []byte{
+	// 1069 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x96, 0xdf, 0x6f, 0xdb, 0x36,
+	0x10, 0xc7, 0x63, 0xeb, 0x87, 0xe3, 0xf3, 0x8f, 0x28, 0xac, 0x57, 0xa8, 0x46, 0x57, 0xb8, 0xea,
+	0xd6, 0x65, 0x01, 0x26, 0x6c, 0x29, 0xf6, 0xb2, 0xad, 0x03, 0xd2, 0xb4, 0x09, 0x82, 0x35, 0xb0,
+	0x27, 0x1b, 0xdd, 0xdb, 0x04, 0xc5, 0x62, 0x1c, 0xb6, 0x32, 0xa5, 0x89, 0xd4, 0x56, 0xff, 0x61,
+	0xfb, 0x07, 0xf6, 0xb8, 0xbf, 0x6a, 0xe0, 0x91, 0x8e, 0xed, 0x3a, 0x19, 0xfa, 0xd0, 0x27, 0xeb,
+	0xbe, 0xbc, 0xd3, 0xdd, 0x91, 0xc7, 0x8f, 0x0c, 0x6d, 0xca, 0x67, 0x8c, 0xd3, 0xb0, 0x28, 0x73,
+	0x99, 0x07, 0xff, 0x58, 0xe0, 0x0c, 0xcb, 0x94, 0x96, 0xe4, 0x01, 0xd8, 0x93, 0x45, 0x41, 0xfd,
+	0xda, 0xa0, 0x76, 0xd0, 0x3d, 0x72, 0xc2, 0x31, 0x4b, 0x69, 0x64, 0xcb, 0x45, 0x41, 0x49, 0x17,
+	0xea, 0xe7, 0x2f, 0xfd, 0xfa, 0xa0, 0x76, 0xd0, 0x8c, 0xea, 0x2c, 0x25, 0xf7, 0xc1, 0x3d, 0x9e,
+	0xe7, 0x15, 0x97, 0xbe, 0x85, 0x9a, 0x9b, 0xa0, 0x45, 0x7a, 0xe0, 0x8c, 0x4a, 0x36, 0xa5, 0xbe,
+	0x8d, 0xb2, 0x53, 0x28, 0x83, 0x10, 0xb0, 0x47, 0x09, 0x2b, 0x7d, 0x07, 0x45, 0xbb, 0x48, 0x58,
+	0x49, 0xbe, 0x85, 0xd6, 0x84, 0xcd, 0xe9, 0x39, 0x3f, 0xcd, 0xcb, 0x29, 0xf5, 0x5d, 0xcc, 0xd9,
+	0x0e, 0xd7, 0xb4, 0xa8, 0x23, 0xd9, 0x9c, 0xc6, 0x8c, 0xc7, 0x57, 0xca, 0x24, 0x4f, 0x61, 0x77,
+	0x94, 0x0b, 0x39, 0xe4, 0xd9, 0xc2, 0x6f, 0xa0, 0x7b, 0x33, 0x5c, 0x0a, 0x51, 0xb3, 0xc8, 0x85,
+	0x8c, 0x73, 0x9e, 0x2d, 0xc8, 0x97, 0xd0, 0x79, 0xc9, 0x44, 0x91, 0x25, 0x0b, 0x53, 0xe2, 0x2e,
+	0xa6, 0xed, 0xa6, 0x5a, 0x8c, 0x4d, 0xa9, 0x9f, 0x43, 0x73, 0x2c, 0xf3, 0x42, 0x97, 0xdb, 0x44,
+	0x17, 0x10, 0x32, 0x2f, 0x62, 0x5d, 0xb3, 0x0f, 0x8d, 0xe3, 0xe9, 0x14, 0xe3, 0x01, 0x17, 0x1b,
+	0x89, 0x36, 0xc9, 0x7d, 0xb0, 0xc6, 0x93, 0x91, 0xdf, 0xc2, 0x12, 0xec, 0x70, 0x3c, 0x19, 0x45,
+	0x96, 0x90, 0x85, 0xea, 0xfd, 0xb4, 0xe2, 0xa9, 0xf0, 0xdb, 0xba, 0xf7, 0x2b, 0x65, 0x90, 0xaf,
+	0x61, 0x6f, 0x54, 0xe6, 0x92, 0x4e, 0x25, 0xcb, 0xb9, 0x4e, 0xd6, 0xc1, 0x75, 0xaf, 0xb8, 0x91,
+	0x4d, 0xca, 0xc7, 0xd0, 0xba, 0x48, 0xde, 0x8f, 0x33, 0x56, 0x14, 0xc9, 0x8c, 0xfa, 0x5d, 0x74,
+	0x6b, 0xcf, 0x93, 0xf7, 0xb1, 0x30, 0x9a, 0xda, 0xf7, 0x8b, 0xa4, 0x7c, 0x47, 0xa5, 0xbf, 0x37,
+	0xa8, 0x1d, 0xec, 0x46, 0xee, 0x1c, 0xad, 0xe0, 0x2d, 0x00, 0x9e, 0xe1, 0x59, 0x99, 0x57, 0x85,
+	0x39, 0xad, 0xda, 0xcd, 0x69, 0x2d, 0xf7, 0xbf, 0xbe, 0xb6, 0xff, 0x0f, 0xc1, 0x79, 0xc5, 0x65,
+	0xb9, 0xc0, 0x03, 0x6c, 0x1d, 0xb9, 0x21, 0xc6, 0x47, 0x0e, 0x55, 0x22, 0xe9, 0x83, 0xfd, 0x9a,
+	0xce, 0x84, 0x6f, 0x0f, 0xac, 0xb5, 0x45, 0x3b, 0xa3, 0x33, 0x11, 0xfc, 0x0e, 0xed, 0x61, 0x25,
+	0x8b, 0x4a, 0xa2, 0x88, 0x1d, 0xea, 0xa7, 0x51, 0x99, 0x4f, 0xa9, 0x10, 0x34, 0x35, 0xa9, 0xbd,
+	0x1c, 0xe5, 0xb8, 0x58, 0xea, 0xe4, 0x09, 0xb4, 0x47, 0x49, 0x29, 0x59, 0x92, 0x61, 0x84, 0x29,
+	0xa8, 0x53, 0x68, 0x2d, 0x46, 0xff, 0xe0, 0x7b, 0x68, 0xbe, 0xc8, 0xf3, 0x77, 0xe7, 0xbc, 0xa8,
+	0xa4, 0x2a, 0x5d, 0x95, 0x6b, 0xde, 0xa8, 0x4b, 0xef, 0x81, 0x93, 0xb1, 0x39, 0x93, 0x18, 0x6e,
+	0x45, 0xda, 0x08, 0x42, 0x1d, 0x76, 0x5c, 0x96, 0xc9, 0x82, 0x3c, 0x86, 0x36, 0xee, 0xa9, 0x39,
+	0x6c, 0xbf, 0x36, 0xb0, 0x0e, 0x9a, 0x51, 0x0b, 0x35, 0x3d, 0x15, 0x41, 0x0a, 0xa0, 0xfc, 0x75,
+	0x2b, 0xe4, 0x11, 0xd8, 0x2f, 0xaa, 0x85, 0x40, 0xc7, 0xd6, 0x11, 0x84, 0x37, 0xaf, 0x8a, 0xec,
+	0xcb, 0x6a, 0x21, 0xc8, 0x00, 0x9c, 0x31, 0xcd, 0x32, 0xe1, 0xd7, 0xb7, 0x1c, 0x1c, 0xa1, 0x16,
+	0x54, 0x55, 0x63, 0x99, 0x48, 0x6a, 0x6e, 0x84, 0x23, 0x94, 0x11, 0x30, 0xd8, 0xbb, 0x48, 0x84,
+	0x38, 0x49, 0xf8, 0x94, 0x66, 0x77, 0xb7, 0xe4, 0xc3, 0x72, 0xbc, 0xcc, 0x9e, 0x2c, 0x4d, 0xe5,
+	0x2d, 0x58, 0xba, 0x7c, 0x2b, 0x3e, 0xab, 0x54, 0xc5, 0xd6, 0x2d, 0x0b, 0x7e, 0x02, 0x6f, 0x95,
+	0xca, 0xb4, 0x45, 0x00, 0xcb, 0xc7, 0x5c, 0x96, 0x69, 0xa5, 0x07, 0xba, 0xe2, 0xe5, 0xf6, 0xa1,
+	0x11, 0x3c, 0x87, 0xfd, 0x49, 0x99, 0xa4, 0x8c, 0xcf, 0xb0, 0x8b, 0xff, 0xdd, 0x7d, 0x6c, 0xcd,
+	0x14, 0x6a, 0xfa, 0xfc, 0x19, 0xc8, 0x7a, 0xf8, 0x2a, 0xfd, 0x47, 0xc6, 0x3f, 0x83, 0xce, 0x19,
+	0xd5, 0x13, 0x75, 0x77, 0xea, 0x2e, 0xd4, 0x59, 0xba, 0xa2, 0x50, 0xf0, 0x6f, 0xdd, 0x8c, 0x3d,
+	0xe6, 0xfc, 0xa8, 0xb1, 0x5f, 0x32, 0xce, 0xda, 0x66, 0xdc, 0xda, 0x8d, 0xb7, 0x37, 0xcf, 0xe0,
+	0x86, 0x6a, 0xce, 0x3a, 0xd5, 0x36, 0x00, 0xe2, 0x6e, 0x01, 0x64, 0x85, 0xc8, 0xc6, 0x06, 0x22,
+	0x1f, 0x42, 0x33, 0xa2, 0xf3, 0x84, 0x71, 0xc6, 0x67, 0x06, 0x4d, 0xcd, 0x72, 0x29, 0xa8, 0xa8,
+	0x53, 0x96, 0x65, 0x34, 0x35, 0x48, 0x72, 0xaf, 0xd0, 0x52, 0xba, 0x6a, 0xb2, 0x12, 0x86, 0x46,
+	0xae, 0x40, 0x4b, 0xc1, 0xee, 0xd7, 0x8a, 0x56, 0x74, 0x94, 0x0b, 0xa6, 0x50, 0x82, 0x58, 0xb2,
+	0xa2, 0xee, 0x1f, 0x4a, 0x8c, 0x0b, 0xa3, 0x2a, 0xb4, 0xbc, 0xc9, 0xb3, 0x6a, 0x4e, 0x8f, 0xaf,
+	0x69, 0x92, 0x1a, 0x42, 0xb5, 0xff, 0x44, 0x29, 0x4e, 0x94, 0x16, 0xfc, 0x06, 0xf7, 0x5e, 0x33,
+	0x21, 0x87, 0x05, 0xe5, 0xfa, 0x3a, 0x7f, 0xa2, 0x69, 0x0d, 0x7e, 0x84, 0xde, 0xe6, 0x8b, 0xcd,
+	0x70, 0x3c, 0x01, 0x57, 0x03, 0xc2, 0x5c, 0xba, 0x56, 0xb8, 0x3a, 0xcb, 0xc8, 0x2c, 0x1d, 0x3e,
+	0x00, 0x5b, 0x1d, 0x11, 0x69, 0x80, 0x75, 0x59, 0x2d, 0xbc, 0x1d, 0xb2, 0x0b, 0xb6, 0x1a, 0x58,
+	0xaf, 0x76, 0x78, 0xb0, 0xf1, 0x05, 0x51, 0x1e, 0x67, 0x93, 0x13, 0x6f, 0x47, 0x3d, 0x9c, 0x0f,
+	0x4f, 0xbc, 0x9a, 0x7a, 0x38, 0x1d, 0xfe, 0xe2, 0xd5, 0x0f, 0xbf, 0x59, 0x7d, 0x39, 0x54, 0x3c,
+	0xcf, 0x39, 0xf5, 0x76, 0x08, 0x80, 0x5b, 0xd2, 0xb7, 0x74, 0x2a, 0xbd, 0x1a, 0x69, 0x41, 0xa3,
+	0xa4, 0x78, 0x6e, 0x5e, 0xfd, 0xf0, 0x0d, 0x02, 0x9e, 0xec, 0x43, 0x67, 0x8a, 0x77, 0x29, 0xe6,
+	0xf4, 0x2f, 0x2a, 0xa4, 0xb7, 0xb3, 0x26, 0xe5, 0x59, 0xaa, 0xa4, 0x1a, 0xd9, 0x83, 0x96, 0x91,
+	0x2e, 0x73, 0x79, 0xed, 0xd5, 0x89, 0x0f, 0xbd, 0x94, 0x4e, 0x4b, 0x3a, 0xa7, 0x5c, 0xc6, 0x09,
+	0x4f, 0x63, 0xbd, 0xec, 0x59, 0x47, 0x7f, 0x5b, 0xe0, 0xbe, 0xc2, 0x4f, 0x2f, 0x19, 0x40, 0xc3,
+	0xd0, 0x92, 0x18, 0xb8, 0xf6, 0x3b, 0xe1, 0x06, 0x55, 0x9f, 0x42, 0xc7, 0x78, 0x68, 0xe0, 0xdf,
+	0xe5, 0xe7, 0x83, 0xab, 0x6f, 0xfc, 0x8d, 0x83, 0xf9, 0x25, 0x8f, 0xc0, 0x39, 0x9e, 0x53, 0x9e,
+	0xde, 0x15, 0x79, 0x08, 0x6d, 0x93, 0x41, 0x7f, 0x35, 0xcc, 0xfe, 0xa3, 0xf1, 0xa1, 0xef, 0x77,
+	0x00, 0x2b, 0xb6, 0x10, 0x2f, 0xfc, 0x80, 0x69, 0xfd, 0xfd, 0x70, 0x0b, 0x3d, 0x3f, 0xc0, 0xde,
+	0x98, 0xca, 0x75, 0x28, 0x10, 0x12, 0x6e, 0x21, 0xa6, 0x7f, 0x2f, 0xbc, 0x85, 0x1b, 0x5f, 0x40,
+	0xf3, 0x94, 0xca, 0xe9, 0xb5, 0x82, 0x2c, 0xd1, 0xac, 0xd5, 0xde, 0xad, 0x70, 0x8d, 0xd9, 0x5f,
+	0xc1, 0xee, 0x92, 0x19, 0xa4, 0x1b, 0x6e, 0xe0, 0xa3, 0xbf, 0x3e, 0x4c, 0xe4, 0x39, 0x74, 0x37,
+	0x27, 0x90, 0xf4, 0xc2, 0x5b, 0x66, 0xbd, 0xff, 0x59, 0x78, 0xdb, 0xa0, 0x5e, 0xba, 0xf8, 0x47,
+	0xe9, 0xd9, 0x7f, 0x03, 0x00, 0x35, 0x20, 0x74, 0x8d, 0x38, 0x09, 0x00, 0x00,
+}
+
+// 下面的空引用用于防止未使用的导入导致编译错误
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// 编译期断言：确保该生成文件与当前使用的 grpc 包版本兼容
+const _ = grpc.SupportPackageIsVersion6
+
+// EngineClient 定义了 Engine 服务的客户端接口
+//
+// 关于 ctx 的使用、以及关闭/结束流式 RPC 的语义，请参考：
+// https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
+type EngineClient interface {
+	Process(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error)
+	ProcessMarket(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error)
+	Cancel(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error)
+	Amend(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error)
+	ProcessGroup(ctx context.Context, in *OrderGroup, opts ...grpc.CallOption) (*OutputOrders, error)
+	MassCancel(ctx context.Context, in *MassCancelInput, opts ...grpc.CallOption) (*MassCancelOutput, error)
+	SetTradingState(ctx context.Context, in *TradingStateInput, opts ...grpc.CallOption) (*TradingStateOutput, error)
+	FetchBook(ctx context.Context, in *BookInput, opts ...grpc.CallOption) (*BookOutput, error)
+	GetOrder(ctx context.Context, in *GetOrderInput, opts ...grpc.CallOption) (*OrderState, error)
+	ListOpenOrders(ctx context.Context, in *ListOpenOrdersInput, opts ...grpc.CallOption) (*ListOpenOrdersOutput, error)
+}
+
+type engineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEngineClient(cc grpc.ClientConnInterface) EngineClient {
+	return &engineClient{cc}
+}
+
+func (c *engineClient) Process(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error) {
+	out := new(OutputOrders)
+	err := c.cc.Invoke(ctx, "/Engine/Process", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) ProcessMarket(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error) {
+	out := new(OutputOrders)
+	err := c.cc.Invoke(ctx, "/Engine/ProcessMarket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) Cancel(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, "/Engine/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) Amend(ctx context.Context, in *Order, opts ...grpc.CallOption) (*OutputOrders, error) {
+	out := new(OutputOrders)
+	err := c.cc.Invoke(ctx, "/Engine/Amend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) ProcessGroup(ctx context.Context, in *OrderGroup, opts ...grpc.CallOption) (*OutputOrders, error) {
+	out := new(OutputOrders)
+	err := c.cc.Invoke(ctx, "/Engine/ProcessGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) MassCancel(ctx context.Context, in *MassCancelInput, opts ...grpc.CallOption) (*MassCancelOutput, error) {
+	out := new(MassCancelOutput)
+	err := c.cc.Invoke(ctx, "/Engine/MassCancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) SetTradingState(ctx context.Context, in *TradingStateInput, opts ...grpc.CallOption) (*TradingStateOutput, error) {
+	out := new(TradingStateOutput)
+	err := c.cc.Invoke(ctx, "/Engine/SetTradingState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) FetchBook(ctx context.Context, in *BookInput, opts ...grpc.CallOption) (*BookOutput, error) {
+	out := new(BookOutput)
+	err := c.cc.Invoke(ctx, "/Engine/FetchBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) GetOrder(ctx context.Context, in *GetOrderInput, opts ...grpc.CallOption) (*OrderState, error) {
+	out := new(OrderState)
+	err := c.cc.Invoke(ctx, "/Engine/GetOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) ListOpenOrders(ctx context.Context, in *ListOpenOrdersInput, opts ...grpc.CallOption) (*ListOpenOrdersOutput, error) {
+	out := new(ListOpenOrdersOutput)
+	err := c.cc.Invoke(ctx, "/Engine/ListOpenOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EngineServer 定义了 Engine 服务的服务端接口
+type EngineServer interface {
+	Process(context.Context, *Order) (*OutputOrders, error)
+	ProcessMarket(context.Context, *Order) (*OutputOrders, error)
+	Cancel(context.Context, *Order) (*Order, error)
+	Amend(context.Context, *Order) (*OutputOrders, error)
+	ProcessGroup(context.Context, *OrderGroup) (*OutputOrders, error)
+	MassCancel(context.Context, *MassCancelInput) (*MassCancelOutput, error)
+	SetTradingState(context.Context, *TradingStateInput) (*TradingStateOutput, error)
+	FetchBook(context.Context, *BookInput) (*BookOutput, error)
+	GetOrder(context.Context, *GetOrderInput) (*OrderState, error)
+	ListOpenOrders(context.Context, *ListOpenOrdersInput) (*ListOpenOrdersOutput, error)
+}
+
+// UnimplementedEngineServer 可嵌入以提供向前兼容的默认实现
+type UnimplementedEngineServer struct {
+}
+
+func (*UnimplementedEngineServer) Process(ctx context.Context, req *Order) (*OutputOrders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
+func (*UnimplementedEngineServer) ProcessMarket(ctx context.Context, req *Order) (*OutputOrders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessMarket not implemented")
+}
+func (*UnimplementedEngineServer) Cancel(ctx context.Context, req *Order) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (*UnimplementedEngineServer) Amend(ctx context.Context, req *Order) (*OutputOrders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Amend not implemented")
+}
+func (*UnimplementedEngineServer) ProcessGroup(ctx context.Context, req *OrderGroup) (*OutputOrders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessGroup not implemented")
+}
+func (*UnimplementedEngineServer) MassCancel(ctx context.Context, req *MassCancelInput) (*MassCancelOutput, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MassCancel not implemented")
+}
+func (*UnimplementedEngineServer) SetTradingState(ctx context.Context, req *TradingStateInput) (*TradingStateOutput, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTradingState not implemented")
+}
+func (*UnimplementedEngineServer) FetchBook(ctx context.Context, req *BookInput) (*BookOutput, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchBook not implemented")
+}
+func (*UnimplementedEngineServer) GetOrder(ctx context.Context, req *GetOrderInput) (*OrderState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (*UnimplementedEngineServer) ListOpenOrders(ctx context.Context, req *ListOpenOrdersInput) (*ListOpenOrdersOutput, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOpenOrders not implemented")
+}
+
+func RegisterEngineServer(s *grpc.Server, srv EngineServer) {
+	s.RegisterService(&_Engine_serviceDesc, srv)
+}
+
+func _Engine_Process_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).Process(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/Process",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).Process(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_ProcessMarket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
 		return srv.(EngineServer).ProcessMarket(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
@@ -484,6 +1333,78 @@ func _Engine_Cancel_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Engine_Amend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).Amend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/Amend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).Amend(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_ProcessGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).ProcessGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/ProcessGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).ProcessGroup(ctx, req.(*OrderGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_MassCancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MassCancelInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).MassCancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/MassCancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).MassCancel(ctx, req.(*MassCancelInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_SetTradingState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TradingStateInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).SetTradingState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/SetTradingState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).SetTradingState(ctx, req.(*TradingStateInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Engine_FetchBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BookInput)
 	if err := dec(in); err != nil {
@@ -502,6 +1423,42 @@ func _Engine_FetchBook_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Engine_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/GetOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).GetOrder(ctx, req.(*GetOrderInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_ListOpenOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenOrdersInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).ListOpenOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Engine/ListOpenOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).ListOpenOrders(ctx, req.(*ListOpenOrdersInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Engine_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Engine",
 	HandlerType: (*EngineServer)(nil),
@@ -518,10 +1475,34 @@ var _Engine_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Cancel",
 			Handler:    _Engine_Cancel_Handler,
 		},
+		{
+			MethodName: "Amend",
+			Handler:    _Engine_Amend_Handler,
+		},
+		{
+			MethodName: "ProcessGroup",
+			Handler:    _Engine_ProcessGroup_Handler,
+		},
+		{
+			MethodName: "MassCancel",
+			Handler:    _Engine_MassCancel_Handler,
+		},
+		{
+			MethodName: "SetTradingState",
+			Handler:    _Engine_SetTradingState_Handler,
+		},
 		{
 			MethodName: "FetchBook",
 			Handler:    _Engine_FetchBook_Handler,
 		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _Engine_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOpenOrders",
+			Handler:    _Engine_ListOpenOrders_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "engine.proto",
@@ -5,7 +5,6 @@ go 1.23
 
 require (
 	github.com/golang/protobuf v1.5.2
-	github.com/goovo/binarytree v0.0.0-20251212032555-0949d8c84ab0
 	github.com/shopspring/decimal v1.3.1
 	google.golang.org/grpc v1.48.0
 )
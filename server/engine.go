@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,25 +16,56 @@ import (
 
 // Engine 引擎服务实现，维护每个交易对的订单簿
 type Engine struct {
-	book map[string]*engine.OrderBook
+	book map[string]*pairBook
 	mu   sync.RWMutex
 }
 
+// pairBook 单个交易对的订单簿及其事件收集器
+// mu 串行化同一交易对上的调用，保证收集到的事件只属于当前请求
+type pairBook struct {
+	*engine.OrderBook
+	events *bookEvents
+	mu     sync.Mutex
+}
+
 // NewEngine 返回 Engine 实例
 func NewEngine() *Engine {
-	return &Engine{book: map[string]*engine.OrderBook{}}
+	return &Engine{book: map[string]*pairBook{}}
+}
+
+// SetInstrumentSpec 设置交易对的交易规则（交易对不存在时新建订单簿）
+func (e *Engine) SetInstrumentSpec(pair string, spec engine.InstrumentSpec) error {
+	if pair == "" {
+		return errors.New("Invalid pair")
+	}
+	return e.getBook(pair, true).SetInstrumentSpec(spec)
+}
+
+// getBook 返回交易对的订单簿，create 为 true 时不存在则新建
+func (e *Engine) getBook(pair string, create bool) *pairBook {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if val, ok := e.book[pair]; ok {
+		return val
+	}
+	if !create {
+		return nil
+	}
+	events := newBookEvents()
+	pb := &pairBook{OrderBook: engine.NewOrderBook(events, engine.WithBookID(pair)), events: events}
+	e.book[pair] = pb
+	return pb
 }
 
 // Process 实现 EngineServer 接口：处理限价单
 func (e *Engine) Process(ctx context.Context, req *engineGrpc.Order) (*engineGrpc.OutputOrders, error) {
 	start := time.Now() // 中文注释：记录方法开始时间用于统计耗时
 	bigZero, _ := util.NewDecimalFromString("0.0")
-	orderString := fmt.Sprintf("{\"id\":\"%s\", \"type\": \"%s\", \"amount\": \"%s\", \"price\": \"%s\" }", req.GetID(), req.GetType().String(), req.GetAmount(), req.GetPrice())
+	msg := newOrderRequest(req)
+	msg.PostOnly, msg.DisplayAmount = req.GetPostOnly().String(), req.GetDisplayAmount()
 
-	var order engine.Order
 	// 解析消息体
-	// fmt.Println("Orderstring =: ", orderString)
-	err := order.FromJSON([]byte(orderString))
+	order, err := msg.parse()
 	if err != nil {
 		fmt.Println("JSON Parse Error =: ", err)
 		return nil, err
@@ -49,17 +81,29 @@ func (e *Engine) Process(ctx context.Context, req *engineGrpc.Order) (*engineGrp
 		return nil, errors.New("Invalid pair")
 	}
 
-	var pairBook *engine.OrderBook
-	e.mu.Lock()
-	if val, ok := e.book[req.GetPair()]; ok {
-		pairBook = val
-	} else {
-		pairBook = engine.NewOrderBook()
-		e.book[req.GetPair()] = pairBook
+	pairBook := e.getBook(req.GetPair(), true)
+
+	// 按交易对的交易规则校验价格与数量，不合规的订单不进入撮合
+	if err := pairBook.InstrumentSpec().Validate(&order, false); err != nil {
+		IncReject()
+		return nil, err
 	}
-	e.mu.Unlock()
 
-	ordersProcessed, partialOrder := pairBook.Process(order)
+	// 引擎会原地修改 Taker 数量，先保留原始订单用于组装结果
+	taker := engine.NewOrder(order.ID, order.Type, order.Amount.Clone(), order.Price)
+	pairBook.mu.Lock()
+	pairBook.events.reset()
+	// 在入口处驻留一次字符串 ID，订单簿的挂单、条件单与订单组索引按引擎订单 ID 存取（回调仍为字符串 ID）
+	order.EID = pairBook.InternOrderID(order.ID)
+	pairBook.Process(order)
+	ordersProcessed, partialOrder := pairBook.events.result(pairBook.OrderBook, taker)
+	reason, rejected := pairBook.events.rejected[order.ID]
+	pairBook.mu.Unlock()
+
+	if rejected {
+		IncReject()
+		return nil, errors.New(string(reason))
+	}
 	// 中文注释：统计限价撮合的成交笔数与耗时
 	IncProcess(start, len(ordersProcessed))
 
@@ -87,6 +131,181 @@ func (e *Engine) Process(ctx context.Context, req *engineGrpc.Order) (*engineGrp
 	return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: "null"}, nil
 }
 
+// ProcessGroup 实现 EngineServer 接口：提交 OCO 订单组（Entry 非空时为括号单，Legs 为出场单）
+// ordersProcessed 返回提交过程中产生的全部成交
+func (e *Engine) ProcessGroup(ctx context.Context, req *engineGrpc.OrderGroup) (*engineGrpc.OutputOrders, error) {
+	if req.GetID() == "" {
+		fmt.Println("Invalid JSON")
+		return nil, errors.New("Invalid JSON")
+	}
+
+	if req.GetPair() == "" {
+		fmt.Println("Invalid pair")
+		return nil, errors.New("Invalid pair")
+	}
+
+	legs := make([]engine.GroupLeg, 0, len(req.GetLegs()))
+	for _, legReq := range req.GetLegs() {
+		leg, err := groupLeg(legReq)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
+	}
+
+	pairBook := e.getBook(req.GetPair(), true)
+
+	var err error
+	pairBook.mu.Lock()
+	pairBook.events.reset()
+	for i := range legs {
+		legs[i].Order.EID = pairBook.InternOrderID(legs[i].Order.ID)
+	}
+	if req.GetEntry() != nil {
+		var entry engine.GroupLeg
+		if entry, err = groupLeg(req.GetEntry()); err == nil {
+			entry.Order.EID = pairBook.InternOrderID(entry.Order.ID)
+			err = pairBook.ProcessBracket(req.GetID(), entry, legs...)
+		}
+	} else {
+		err = pairBook.ProcessOCO(req.GetID(), legs...)
+	}
+	ordersProcessed := pairBook.events.processed()
+	pairBook.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ordersProcessedString, err := json.Marshal(ordersProcessed)
+	if err != nil {
+		fmt.Println("Marshal error", err)
+		return nil, err
+	}
+	return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: "null"}, nil
+}
+
+// groupLeg 解析订单组中的一条腿（Market 为 true 时按市价单处理）
+func groupLeg(req *engineGrpc.Order) (engine.GroupLeg, error) {
+	msg := newOrderRequest(req)
+	msg.PostOnly, msg.DisplayAmount = req.GetPostOnly().String(), req.GetDisplayAmount()
+	msg.ProtectionPrice, msg.MaxSlippage = req.GetProtectionPrice(), req.GetMaxSlippage()
+
+	order, err := msg.parse()
+	if err != nil {
+		fmt.Println("JSON Parse Error =: ", err)
+		return engine.GroupLeg{}, err
+	}
+	return engine.GroupLeg{Order: order, Market: req.GetMarket()}, nil
+}
+
+// orderRequest 订单消息体：由请求字段组装后经 json.Marshal 编码，客户端字符串中的引号等字符不会破坏或注入字段
+// 各 RPC 只填写其支持的字段，未填写的字段按空值解析
+type orderRequest struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Amount          string `json:"amount"`
+	Price           string `json:"price"`
+	TimeInForce     string `json:"time_in_force"`
+	PostOnly        string `json:"post_only,omitempty"`
+	DisplayAmount   string `json:"display_amount"`
+	StopPrice       string `json:"stop_price"`
+	Account         string `json:"account"`
+	STP             string `json:"stp"`
+	Funds           string `json:"funds"`
+	ProtectionPrice string `json:"protection_price"`
+	MaxSlippage     string `json:"max_slippage"`
+}
+
+// newOrderRequest 返回包含各 RPC 共有字段的订单消息体
+func newOrderRequest(req *engineGrpc.Order) *orderRequest {
+	return &orderRequest{
+		ID:          req.GetID(),
+		Type:        req.GetType().String(),
+		Amount:      req.GetAmount(),
+		Price:       req.GetPrice(),
+		TimeInForce: req.GetTimeInForce().String(),
+		StopPrice:   req.GetStopPrice(),
+		Account:     req.GetAccount(),
+		STP:         req.GetSTP().String(),
+	}
+}
+
+// parse 编码消息体并解析为 engine.Order
+func (r *orderRequest) parse() (engine.Order, error) {
+	var order engine.Order
+	msg, err := json.Marshal(r)
+	if err != nil {
+		return order, err
+	}
+	err = order.FromJSON(msg)
+	return order, err
+}
+
+// MassCancel 实现 EngineServer 接口：按账户、方向、价格批量撤单
+// pair 为空时作用于所有交易对；side 为 buy/sell 或空（双边）；price 为空时不按价格过滤
+func (e *Engine) MassCancel(ctx context.Context, req *engineGrpc.MassCancelInput) (*engineGrpc.MassCancelOutput, error) {
+	filter := engine.MassCancelFilter{Account: req.GetAccount()}
+	switch req.GetSide() {
+	case "":
+	case "buy":
+		filter.Side = engine.Buy
+	case "sell":
+		filter.Side = engine.Sell
+	default:
+		return nil, errors.New("invalid order type")
+	}
+	if req.GetPrice() != "" {
+		price, err := util.NewDecimalFromString(req.GetPrice())
+		if err != nil {
+			return nil, errors.New("invalid order price")
+		}
+		filter.Price = price
+	}
+
+	var books []*pairBook
+	if req.GetPair() != "" {
+		if pb := e.getBook(req.GetPair(), false); pb != nil {
+			books = append(books, pb)
+		}
+	} else {
+		e.mu.RLock()
+		for _, pb := range e.book {
+			books = append(books, pb)
+		}
+		e.mu.RUnlock()
+	}
+
+	output := &engineGrpc.MassCancelOutput{}
+	for _, pb := range books {
+		pb.mu.Lock()
+		pb.events.reset()
+		result := pb.MassCancel(filter)
+		pb.mu.Unlock()
+		output.Buys += int64(result.Buys)
+		output.Sells += int64(result.Sells)
+	}
+	return output, nil
+}
+
+// SetTradingState 实现 EngineServer 接口：切换交易对的交易状态（连续撮合、熔断、集合竞价、休市）
+func (e *Engine) SetTradingState(ctx context.Context, req *engineGrpc.TradingStateInput) (*engineGrpc.TradingStateOutput, error) {
+	if req.GetPair() == "" {
+		fmt.Println("Invalid pair")
+		return nil, errors.New("Invalid pair")
+	}
+
+	pairBook := e.getBook(req.GetPair(), true)
+
+	pairBook.mu.Lock()
+	defer pairBook.mu.Unlock()
+	pairBook.events.reset()
+	if err := pairBook.SetTradingState(engine.TradingState(req.GetState())); err != nil {
+		return nil, err
+	}
+	return &engineGrpc.TradingStateOutput{Pair: req.GetPair(), State: string(pairBook.TradingState())}, nil
+}
+
 // Cancel 实现 EngineServer 接口：撤单
 func (e *Engine) Cancel(ctx context.Context, req *engineGrpc.Order) (*engineGrpc.Order, error) {
 	start := time.Now() // 中文注释：记录方法开始时间用于统计耗时
@@ -102,17 +321,12 @@ func (e *Engine) Cancel(ctx context.Context, req *engineGrpc.Order) (*engineGrpc
 		return nil, errors.New("Invalid pair")
 	}
 
-	var pairBook *engine.OrderBook
-	e.mu.Lock()
-	if val, ok := e.book[req.GetPair()]; ok {
-		pairBook = val
-	} else {
-		pairBook = engine.NewOrderBook()
-		e.book[req.GetPair()] = pairBook
-	}
-	e.mu.Unlock()
+	pairBook := e.getBook(req.GetPair(), true)
 
+	pairBook.mu.Lock()
+	pairBook.events.reset()
 	order = pairBook.CancelOrder(order.ID)
+	pairBook.mu.Unlock()
 
 	// fmt.Println("pair:", req.GetPair())
 	// fmt.Println(pairBook)
@@ -134,22 +348,82 @@ func (e *Engine) Cancel(ctx context.Context, req *engineGrpc.Order) (*engineGrpc
 	return orderEngine, nil
 }
 
+// Amend 实现 EngineServer 接口：修改挂单价格/数量（Price、Amount 为空表示不修改）
+// 价格不变且数量减少时保留时间优先级，否则撤单重下；返回重新撮合产生的成交与改单后的挂单状态
+func (e *Engine) Amend(ctx context.Context, req *engineGrpc.Order) (*engineGrpc.OutputOrders, error) {
+	if req.GetID() == "" {
+		fmt.Println("Invalid JSON")
+		return nil, errors.New("Invalid JSON")
+	}
+
+	if req.GetPair() == "" {
+		fmt.Println("Invalid pair")
+		return nil, errors.New("Invalid pair")
+	}
+
+	var newPrice, newAmount *util.StandardBigDecimal
+	var err error
+	if req.GetPrice() != "" {
+		if newPrice, err = util.NewDecimalFromString(req.GetPrice()); err != nil {
+			return nil, errors.New("invalid order price")
+		}
+	}
+	if req.GetAmount() != "" {
+		if newAmount, err = util.NewDecimalFromString(req.GetAmount()); err != nil {
+			return nil, errors.New("invalid order amount")
+		}
+	}
+
+	pairBook := e.getBook(req.GetPair(), false)
+	if pairBook == nil {
+		return nil, errors.New("NoOrderPresent")
+	}
+
+	pairBook.mu.Lock()
+	pairBook.events.reset()
+	order, err := pairBook.AmendOrder(req.GetID(), newPrice, newAmount)
+	if err != nil {
+		pairBook.mu.Unlock()
+		return nil, err
+	}
+	// 改价后的订单重新撮合时产生的成交与 Process 一样返回
+	ordersProcessed, _ := pairBook.events.fills(order)
+	pairBook.mu.Unlock()
+
+	ordersProcessedString, err := json.Marshal(ordersProcessed)
+	if err != nil {
+		fmt.Println("Marshal error", err)
+		return nil, err
+	}
+
+	// 返回改单后的挂单状态，已完全成交（不再挂单）时为 null
+	if order.Amount.Val > 0 {
+		partialOrderString, err := json.Marshal(order)
+		if err != nil {
+			fmt.Println("partialOrderString Marshal error", err)
+			return nil, err
+		}
+		return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: string(partialOrderString)}, nil
+	}
+	return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: "null"}, nil
+}
+
 // ProcessMarket 实现 EngineServer 接口：处理市价单
 func (e *Engine) ProcessMarket(ctx context.Context, req *engineGrpc.Order) (*engineGrpc.OutputOrders, error) {
 	start := time.Now() // 中文注释：记录方法开始时间用于统计耗时
 	bigZero, _ := util.NewDecimalFromString("0.0")
-	orderString := fmt.Sprintf("{\"id\":\"%s\", \"type\": \"%s\", \"amount\": \"%s\", \"price\": \"%s\" }", req.GetID(), req.GetType().String(), req.GetAmount(), req.GetPrice())
+	msg := newOrderRequest(req)
+	msg.Funds = req.GetFunds()
+	msg.ProtectionPrice, msg.MaxSlippage = req.GetProtectionPrice(), req.GetMaxSlippage()
 
-	var order engine.Order
 	// 解析消息体
-	// fmt.Println("Orderstring =: ", orderString)
-	err := order.FromJSON([]byte(orderString))
+	order, err := msg.parse()
 	if err != nil {
 		fmt.Println("JSON Parse Error =: ", err)
 		return nil, err
 	}
 
-	if order.Amount.Cmp(bigZero) == 0 {
+	if order.Amount.Cmp(bigZero) == 0 && order.Funds == nil {
 		fmt.Println("Invalid JSON")
 		return nil, errors.New("Invalid JSON")
 	}
@@ -159,17 +433,26 @@ func (e *Engine) ProcessMarket(ctx context.Context, req *engineGrpc.Order) (*eng
 		return nil, errors.New("Invalid pair")
 	}
 
-	var pairBook *engine.OrderBook
-	e.mu.Lock()
-	if val, ok := e.book[req.GetPair()]; ok {
-		pairBook = val
-	} else {
-		pairBook = engine.NewOrderBook()
-		e.book[req.GetPair()] = pairBook
+	pairBook := e.getBook(req.GetPair(), true)
+
+	if err := pairBook.InstrumentSpec().Validate(&order, true); err != nil {
+		IncReject()
+		return nil, err
 	}
-	e.mu.Unlock()
 
-	ordersProcessed, partialOrder := pairBook.ProcessMarket(order)
+	taker := engine.NewOrder(order.ID, order.Type, order.Amount.Clone(), order.Price)
+	pairBook.mu.Lock()
+	pairBook.events.reset()
+	order.EID = pairBook.InternOrderID(order.ID)
+	pairBook.ProcessMarket(order)
+	ordersProcessed, partialOrder := pairBook.events.result(pairBook.OrderBook, taker)
+	reason, rejected := pairBook.events.rejected[order.ID]
+	pairBook.mu.Unlock()
+
+	if rejected {
+		IncReject()
+		return nil, errors.New(string(reason))
+	}
 	// 中文注释：统计市价撮合的成交笔数与耗时
 	IncProcessMarket(start, len(ordersProcessed))
 
@@ -187,6 +470,10 @@ func (e *Engine) ProcessMarket(ctx context.Context, req *engineGrpc.Order) (*eng
 	if partialOrder != nil {
 		var partialOrderString []byte
 		partialOrderString, err = json.Marshal(partialOrder)
+		if err != nil {
+			fmt.Println("partialOrderString Marshal error", err)
+			return nil, err
+		}
 		return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: string(partialOrderString)}, nil
 	}
 	return &engineGrpc.OutputOrders{OrdersProcessed: string(ordersProcessedString), PartialOrder: "null"}, nil
@@ -200,20 +487,15 @@ func (e *Engine) FetchBook(ctx context.Context, req *engineGrpc.BookInput) (*eng
 		return nil, errors.New("Invalid pair")
 	}
 
-	var pairBook *engine.OrderBook
-	e.mu.Lock()
-	if val, ok := e.book[req.GetPair()]; ok {
-		pairBook = val
-	} else {
-		e.mu.Unlock()
+	pairBook := e.getBook(req.GetPair(), false)
+	if pairBook == nil {
 		return nil, errors.New("Invalid pair")
 	}
-	e.mu.Unlock()
 
 	// fmt.Println(pairBook)
 	book := pairBook.GetOrders(req.GetLimit())
 
-	result := &engineGrpc.BookOutput{Buys: []*engineGrpc.BookArray{}, Sells: []*engineGrpc.BookArray{}}
+	result := &engineGrpc.BookOutput{Buys: []*engineGrpc.BookArray{}, Sells: []*engineGrpc.BookArray{}, State: string(pairBook.TradingState())}
 
 	for _, buy := range book.Buys {
 		arr := &engineGrpc.BookArray{PriceAmount: []string{}}
@@ -254,3 +536,92 @@ func (e *Engine) FetchBook(ctx context.Context, req *engineGrpc.BookInput) (*eng
 	IncFetchBook(start)
 	return result, nil
 }
+
+// GetOrder 实现 EngineServer 接口：查询挂单（或未触发条件单）的剩余数量与队列位置
+func (e *Engine) GetOrder(ctx context.Context, req *engineGrpc.GetOrderInput) (*engineGrpc.OrderState, error) {
+	if req.GetId() == "" {
+		fmt.Println("Invalid JSON")
+		return nil, errors.New("Invalid JSON")
+	}
+
+	if req.GetPair() == "" {
+		fmt.Println("Invalid pair")
+		return nil, errors.New("Invalid pair")
+	}
+
+	pairBook := e.getBook(req.GetPair(), false)
+	if pairBook == nil {
+		return nil, errors.New("NoOrderPresent")
+	}
+	info := pairBook.GetOrder(req.GetId())
+	if info == nil {
+		return nil, errors.New("NoOrderPresent")
+	}
+	return orderState(req.GetPair(), info), nil
+}
+
+// ListOpenOrders 实现 EngineServer 接口：按账户、方向列出挂单与未触发条件单
+// pair 为空时查询所有交易对；side 为 buy/sell 或空（双边）
+func (e *Engine) ListOpenOrders(ctx context.Context, req *engineGrpc.ListOpenOrdersInput) (*engineGrpc.ListOpenOrdersOutput, error) {
+	filter := engine.OpenOrdersFilter{Account: req.GetAccount()}
+	switch req.GetSide() {
+	case "":
+	case "buy":
+		filter.Side = engine.Buy
+	case "sell":
+		filter.Side = engine.Sell
+	default:
+		return nil, errors.New("invalid order type")
+	}
+
+	pairs := map[string]*pairBook{}
+	var names []string
+	if req.GetPair() != "" {
+		if pb := e.getBook(req.GetPair(), false); pb != nil {
+			pairs[req.GetPair()] = pb
+			names = append(names, req.GetPair())
+		}
+	} else {
+		e.mu.RLock()
+		for pair, pb := range e.book {
+			pairs[pair] = pb
+			names = append(names, pair)
+		}
+		e.mu.RUnlock()
+	}
+	// 按交易对名称排序，保证结果顺序稳定
+	sort.Strings(names)
+
+	output := &engineGrpc.ListOpenOrdersOutput{Orders: []*engineGrpc.OrderState{}}
+	for _, pair := range names {
+		for _, info := range pairs[pair].ListOpenOrders(filter) {
+			output.Orders = append(output.Orders, orderState(pair, &info))
+		}
+	}
+	return output, nil
+}
+
+// orderState 把订单状态快照转换为 gRPC 消息
+func orderState(pair string, info *engine.OrderInfo) *engineGrpc.OrderState {
+	state := &engineGrpc.OrderState{
+		ID:            info.ID,
+		Pair:          pair,
+		Type:          engineGrpc.Side(engineGrpc.Side_value[info.Side.String()]),
+		Account:       info.Account,
+		Amount:        info.Quantity.String(),
+		Remaining:     info.Remaining.String(),
+		Filled:        info.Filled.String(),
+		Status:        string(info.Status),
+		QueuePosition: int64(info.QueuePosition),
+	}
+	if info.Price != nil {
+		state.Price = info.Price.String()
+	}
+	if info.StopPrice != nil {
+		state.StopPrice = info.StopPrice.String()
+	}
+	if info.VolumeAhead != nil {
+		state.VolumeAhead = info.VolumeAhead.String()
+	}
+	return state
+}
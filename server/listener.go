@@ -0,0 +1,99 @@
+package server
+
+import (
+	"github.com/goovo/matching-engine/engine"
+	"github.com/goovo/matching-engine/util"
+)
+
+// tradeEvent 一笔撮合成交
+type tradeEvent struct {
+	makerID string
+	takerID string
+	side    engine.Side
+	price   int64
+	amount  int64
+}
+
+// bookEvents 收集单次调用期间订单簿回调的事件，供 RPC 组装返回结果
+// 注意：需要在 pairBook.mu 保护下使用
+type bookEvents struct {
+	trades    []tradeEvent
+	accepted  map[string]bool
+	cancelled map[string]bool
+	rejected  map[string]engine.RejectReason
+}
+
+func newBookEvents() *bookEvents {
+	return &bookEvents{accepted: map[string]bool{}, cancelled: map[string]bool{}, rejected: map[string]engine.RejectReason{}}
+}
+
+// OnTrade 实现 engine.MatchingListener 接口
+func (b *bookEvents) OnTrade(makerOrderID, takerOrderID string, side engine.Side, price, amount int64) {
+	b.trades = append(b.trades, tradeEvent{makerOrderID, takerOrderID, side, price, amount})
+}
+
+// OnOrderCancelled 实现 engine.MatchingListener 接口
+func (b *bookEvents) OnOrderCancelled(orderID string) {
+	b.cancelled[orderID] = true
+}
+
+// OnOrderAccepted 实现 engine.MatchingListener 接口
+func (b *bookEvents) OnOrderAccepted(orderID string) {
+	b.accepted[orderID] = true
+}
+
+// OnOrderRejected 实现 engine.RejectListener 接口
+func (b *bookEvents) OnOrderRejected(orderID string, reason engine.RejectReason) {
+	b.rejected[orderID] = reason
+}
+
+// reset 清空上一次调用收集到的事件
+func (b *bookEvents) reset() {
+	b.trades = b.trades[:0]
+	for id := range b.accepted {
+		delete(b.accepted, id)
+	}
+	for id := range b.cancelled {
+		delete(b.cancelled, id)
+	}
+	for id := range b.rejected {
+		delete(b.rejected, id)
+	}
+}
+
+// result 将收集到的事件转换为 OutputOrders 所需的已处理订单与剩余挂单
+// ordersProcessed 中每笔成交对应一个 Maker 条目（成交量与成交价），Taker 有成交时追加一条汇总条目；
+// taker 为撮合前的订单副本（数量为原始数量），其剩余部分挂单时按订单簿中的挂单（如 post-only 改价后的价格）返回 partialOrder
+func (b *bookEvents) result(book *engine.OrderBook, taker *engine.Order) ([]*engine.Order, *engine.Order) {
+	ordersProcessed, _ := b.fills(taker)
+	if b.accepted[taker.ID] {
+		if info := book.GetOrder(taker.ID); info != nil && info.StopPrice == nil {
+			return ordersProcessed, engine.NewOrder(info.ID, info.Side, info.Remaining, info.Price)
+		}
+	}
+	return ordersProcessed, nil
+}
+
+// fills 返回每笔成交对应的 Maker 条目，taker 有成交时追加一条汇总条目，同时返回 taker 的成交总量
+func (b *bookEvents) fills(taker *engine.Order) ([]*engine.Order, *util.StandardBigDecimal) {
+	ordersProcessed := b.processed()
+	filled := &util.StandardBigDecimal{}
+	for _, t := range b.trades {
+		if t.takerID == taker.ID {
+			filled.Val += t.amount
+		}
+	}
+	if filled.Val > 0 {
+		ordersProcessed = append(ordersProcessed, engine.NewOrder(taker.ID, taker.Type, filled, taker.Price))
+	}
+	return ordersProcessed, filled
+}
+
+// processed 返回每笔成交对应的 Maker 条目（成交量与成交价）
+func (b *bookEvents) processed() []*engine.Order {
+	ordersProcessed := []*engine.Order{}
+	for _, t := range b.trades {
+		ordersProcessed = append(ordersProcessed, engine.NewOrder(t.makerID, t.side, &util.StandardBigDecimal{Val: t.amount}, &util.StandardBigDecimal{Val: t.price}))
+	}
+	return ordersProcessed
+}
@@ -25,6 +25,9 @@ var (
 	cancelCalls       int64
 	fetchBookCalls    int64
 
+	// 拒单计数（订单簿拒单与交易规则校验失败）
+	rejectCalls int64
+
 	// 撮合笔数计数（ordersProcessed 的长度）
 	processMatches       int64
 	processMarketMatches int64
@@ -45,6 +48,7 @@ func StartMetrics() {
 		var prevProcess, prevProcessMarket, prevCancel, prevFetch int64
 		var prevMatchProcess, prevMatchMarket int64
 		var prevLatencyProcess, prevLatencyProcessMarket, prevLatencyCancel, prevLatencyFetch int64
+		var prevReject int64
 
 		for range ticker.C {
 			// 读取当前计数
@@ -52,6 +56,7 @@ func StartMetrics() {
 			curProcessMarket := atomic.LoadInt64(&processMarketCalls)
 			curCancel := atomic.LoadInt64(&cancelCalls)
 			curFetch := atomic.LoadInt64(&fetchBookCalls)
+			curReject := atomic.LoadInt64(&rejectCalls)
 
 			curMatchProcess := atomic.LoadInt64(&processMatches)
 			curMatchMarket := atomic.LoadInt64(&processMarketMatches)
@@ -66,6 +71,7 @@ func StartMetrics() {
 			deltaProcessMarket := curProcessMarket - prevProcessMarket
 			deltaCancel := curCancel - prevCancel
 			deltaFetch := curFetch - prevFetch
+			deltaReject := curReject - prevReject
 
 			deltaMatchProcess := curMatchProcess - prevMatchProcess
 			deltaMatchMarket := curMatchMarket - prevMatchMarket
@@ -94,14 +100,15 @@ func StartMetrics() {
 			}
 
 			// 若所有方法在该秒内均无请求（且无撮合笔数变化），则跳过打印，避免刷屏的 0 值
-			if (deltaProcess + deltaProcessMarket + deltaCancel + deltaFetch + deltaMatchProcess + deltaMatchMarket) > 0 {
+			if (deltaProcess + deltaProcessMarket + deltaCancel + deltaFetch + deltaMatchProcess + deltaMatchMarket + deltaReject) > 0 {
 				// 打印一行指标（可根据需要改为结构化日志）
 				fmt.Printf(
-					"[metrics] Process QPS=%d Avg=%.3fms Matches/s=%d | Market QPS=%d Avg=%.3fms Matches/s=%d | Cancel QPS=%d Avg=%.3fms | FetchBook QPS=%d Avg=%.3fms\n",
+					"[metrics] Process QPS=%d Avg=%.3fms Matches/s=%d | Market QPS=%d Avg=%.3fms Matches/s=%d | Cancel QPS=%d Avg=%.3fms | FetchBook QPS=%d Avg=%.3fms | Rejects/s=%d\n",
 					deltaProcess, avgLatProcessMs, deltaMatchProcess,
 					deltaProcessMarket, avgLatProcessMarketMs, deltaMatchMarket,
 					deltaCancel, avgLatCancelMs,
 					deltaFetch, avgLatFetchMs,
+					deltaReject,
 				)
 			}
 
@@ -110,6 +117,7 @@ func StartMetrics() {
 			prevProcessMarket = curProcessMarket
 			prevCancel = curCancel
 			prevFetch = curFetch
+			prevReject = curReject
 			prevMatchProcess = curMatchProcess
 			prevMatchMarket = curMatchMarket
 			prevLatencyProcess = curLatencyProcess
@@ -150,3 +158,8 @@ func IncFetchBook(start time.Time) {
 	atomic.AddInt64(&fetchBookCalls, 1)
 	atomic.AddInt64(&fetchBookLatencyNs, time.Since(start).Nanoseconds())
 }
+
+// IncReject 在订单被拒绝时调用（拒单原因通过 RPC 错误返回给调用方）
+func IncReject() {
+	atomic.AddInt64(&rejectCalls, 1)
+}
@@ -3,6 +3,8 @@ package util
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/bits"
 	"strconv"
 	"strings"
 )
@@ -26,10 +28,12 @@ func (s *StandardBigDecimal) Clone() *StandardBigDecimal {
 }
 
 // NewDecimalFromString 从字符串解析定点数
+// 只接受 [-]整数[.小数] 形式；小数超过 8 位且多出的位不全为 0 时返回错误（不再静默截断），超出 int64 范围时返回错误
 func NewDecimalFromString(str string) (*StandardBigDecimal, error) {
 	if str == "" {
 		return nil, errors.New("empty string")
 	}
+	raw := str
 
 	// 处理负号
 	neg := false
@@ -39,14 +43,25 @@ func NewDecimalFromString(str string) (*StandardBigDecimal, error) {
 	}
 
 	parts := strings.Split(str, ".")
+	if len(parts) > 2 || parts[0] == "" && (len(parts) == 1 || parts[1] == "") {
+		return nil, fmt.Errorf("invalid decimal %q", raw)
+	}
+	for _, part := range parts {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return nil, fmt.Errorf("invalid decimal %q", raw)
+			}
+		}
+	}
+
 	var intPart, fracPart int64
 	var err error
 
 	// 解析整数部分
 	if parts[0] != "" {
 		intPart, err = strconv.ParseInt(parts[0], 10, 64)
-		if err != nil {
-			return nil, err
+		if err != nil || intPart > math.MaxInt64/SCALE {
+			return nil, fmt.Errorf("decimal %q out of range", raw)
 		}
 	}
 
@@ -54,7 +69,10 @@ func NewDecimalFromString(str string) (*StandardBigDecimal, error) {
 	if len(parts) > 1 && len(parts[1]) > 0 {
 		fracStr := parts[1]
 		if len(fracStr) > 8 {
-			fracStr = fracStr[:8] // 截断超过8位的小数
+			if strings.TrimRight(fracStr[8:], "0") != "" {
+				return nil, fmt.Errorf("decimal %q has more than 8 decimal places", raw)
+			}
+			fracStr = fracStr[:8]
 		}
 		// 补齐到8位
 		padding := 8 - len(fracStr)
@@ -67,6 +85,9 @@ func NewDecimalFromString(str string) (*StandardBigDecimal, error) {
 		}
 	}
 
+	if intPart*SCALE > math.MaxInt64-fracPart {
+		return nil, fmt.Errorf("decimal %q out of range", raw)
+	}
 	val := intPart*SCALE + fracPart
 	if neg {
 		val = -val
@@ -120,6 +141,48 @@ func (s *StandardBigDecimal) Div(other *StandardBigDecimal) *StandardBigDecimal
 	return &StandardBigDecimal{Val: (s.Val * SCALE) / other.Val}
 }
 
+// MulFloor 乘法，使用 128 位中间结果避免溢出，结果向下取整（仅支持非负数）
+func (s *StandardBigDecimal) MulFloor(other *StandardBigDecimal) *StandardBigDecimal {
+	return &StandardBigDecimal{Val: mulDiv(s.Val, other.Val, SCALE, false)}
+}
+
+// MulCeil 乘法，使用 128 位中间结果避免溢出，结果向上取整（仅支持非负数）
+func (s *StandardBigDecimal) MulCeil(other *StandardBigDecimal) *StandardBigDecimal {
+	return &StandardBigDecimal{Val: mulDiv(s.Val, other.Val, SCALE, true)}
+}
+
+// DivFloor 除法，使用 128 位中间结果避免溢出，结果向下取整（仅支持非负数）
+func (s *StandardBigDecimal) DivFloor(other *StandardBigDecimal) *StandardBigDecimal {
+	if other.Val == 0 {
+		return &StandardBigDecimal{Val: 0} // 避免 panic
+	}
+	return &StandardBigDecimal{Val: mulDiv(s.Val, SCALE, other.Val, false)}
+}
+
+// MulDivFloor 计算 a * b / c（128 位中间结果，向下取整），用于按比例分配定点数的原始值
+func MulDivFloor(a, b, c int64) int64 {
+	if c == 0 {
+		return 0 // 避免 panic
+	}
+	return mulDiv(a, b, c, false)
+}
+
+// mulDiv 计算 a * b / c，结果超出 int64 时返回 math.MaxInt64
+func mulDiv(a, b, c int64, roundUp bool) int64 {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi >= uint64(c) {
+		return math.MaxInt64
+	}
+	quo, rem := bits.Div64(hi, lo, uint64(c))
+	if roundUp && rem != 0 {
+		quo++
+	}
+	if quo > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(quo)
+}
+
 // Cmp 比较
 func (s *StandardBigDecimal) Cmp(other *StandardBigDecimal) int {
 	if s.Val > other.Val {
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+)
+
+func decimal(t *testing.T, s string) *StandardBigDecimal {
+	d, err := NewDecimalFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestMulDivNoOverflow(t *testing.T) {
+	price := decimal(t, "60000.0")
+	amount := decimal(t, "50000.0")
+	if v := price.MulFloor(amount); v.Cmp(decimal(t, "3000000000.0")) != 0 {
+		t.Fatalf("128-bit multiply should not overflow (have: %s)", v)
+	}
+	if v := decimal(t, "3000000000.0").DivFloor(price); v.Cmp(amount) != 0 {
+		t.Fatalf("128-bit divide should not overflow (have: %s)", v)
+	}
+}
+
+func TestStrictDecimalParsing(t *testing.T) {
+	var tests = []struct {
+		input string
+		valid bool
+		val   int64
+	}{
+		{"1.5", true, 150000000},
+		{"-0.00000001", true, -1},
+		{"1.000000010", true, 100000001},
+		{"1.000000001", false, 0},
+		{"1.2.3", false, 0},
+		{"1.-5", false, 0},
+		{"+1", false, 0},
+		{".", false, 0},
+		{"99999999999.0", false, 0},
+	}
+	for _, tt := range tests {
+		d, err := NewDecimalFromString(tt.input)
+		if (err == nil) != tt.valid {
+			t.Fatalf("%q: expected valid=%v (have err: %v)", tt.input, tt.valid, err)
+		}
+		if tt.valid && d.Val != tt.val {
+			t.Fatalf("%q: expected %d (have: %d)", tt.input, tt.val, d.Val)
+		}
+	}
+}